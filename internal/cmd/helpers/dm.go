@@ -1,6 +1,9 @@
 package helpers
 
 import (
+	"fmt"
+	"strings"
+
 	"tas/internal/util"
 )
 
@@ -16,6 +19,26 @@ const (
 	IS
 )
 
+func (p predicateType) String() string {
+	switch p {
+	case LT:
+		return "is less than"
+	case LE:
+		return "is at most"
+	case EQ:
+		return "equals"
+	case GE:
+		return "is at least"
+	case GT:
+		return "is greater than"
+	case INR:
+		return "is in the range"
+	case IS:
+		return "is one of"
+	}
+	return "unknown predicate"
+}
+
 func AdjustZoneDM(currentDM int, adjustDMBy int, valueToTest bool) int {
 	if valueToTest {
 		currentDM += adjustDMBy
@@ -58,33 +81,33 @@ func AdjustDM(ctx *util.TASContext, currentDM int, adjustDMBy int, valueToTest i
 	case LE:
 		requireThresholdCount(1)
 		if valueToTest <= thresholds[0] {
-			return currentDM + adjustDMBy
+			return applyDM(ctx, currentDM, adjustDMBy, valueToTest, predicate, thresholds)
 		}
 	case LT:
 		requireThresholdCount(1)
 		if valueToTest < thresholds[0] {
-			return currentDM + adjustDMBy
+			return applyDM(ctx, currentDM, adjustDMBy, valueToTest, predicate, thresholds)
 		}
 	case EQ:
 		requireThresholdCount(1)
 		if valueToTest == thresholds[0] {
-			return currentDM + adjustDMBy
+			return applyDM(ctx, currentDM, adjustDMBy, valueToTest, predicate, thresholds)
 		}
 	case GE:
 		requireThresholdCount(1)
 		if valueToTest >= thresholds[0] {
-			return currentDM + adjustDMBy
+			return applyDM(ctx, currentDM, adjustDMBy, valueToTest, predicate, thresholds)
 		}
 	case GT:
 		requireThresholdCount(1)
 		if valueToTest > thresholds[0] {
-			return currentDM + adjustDMBy
+			return applyDM(ctx, currentDM, adjustDMBy, valueToTest, predicate, thresholds)
 		}
 	case INR:
 		requireThresholdCount(2)
 		requireThresholdOrdered()
 		if valueToTest >= thresholds[0] && valueToTest <= thresholds[1] {
-			return currentDM + adjustDMBy
+			return applyDM(ctx, currentDM, adjustDMBy, valueToTest, predicate, thresholds)
 		}
 	case IS:
 		requireThresholdMinCount(2)
@@ -96,9 +119,27 @@ func AdjustDM(ctx *util.TASContext, currentDM int, adjustDMBy int, valueToTest i
 			}
 		}
 		if shouldAdjust {
-			return currentDM + adjustDMBy
+			return applyDM(ctx, currentDM, adjustDMBy, valueToTest, predicate, thresholds)
 		}
 		return currentDM
 	}
 	return currentDM
 }
+
+// applies the DM and, if rolls are being audited, notes why it was applied
+func applyDM(ctx *util.TASContext, currentDM int, adjustDMBy int, valueToTest int, predicate predicateType, thresholds []int) int {
+
+	if adjustDMBy != 0 {
+		values := make([]string, 0, len(thresholds))
+		for _, t := range thresholds {
+			values = append(values, fmt.Sprintf("%d", t))
+		}
+		separator := ","
+		if predicate == INR {
+			separator = "-"
+		}
+		ctx.Audit().Reason(fmt.Sprintf("DM%+d (value %d %s %s)", adjustDMBy, valueToTest, predicate, strings.Join(values, separator)))
+	}
+
+	return currentDM + adjustDMBy
+}
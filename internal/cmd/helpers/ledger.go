@@ -0,0 +1,89 @@
+package helpers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"tas/internal/model"
+	"tas/internal/util"
+)
+
+/*
+	The campaign ledger (see the ledger command) keeps the state of a campaign between commands. It lives in
+	data-local; each change keeps the old file with a .bak extension. It is read here so that both the ledger and
+	trade commands can use it; the trade commands use its current world when none is given
+*/
+
+const (
+	LedgerFileFlagName    = "ledger"
+	DefaultLedgerFilename = "ledger.json"
+
+	defaultLedgerPath = "./data-local/"
+	ledgerBackupExt   = ".bak"
+)
+
+// LedgerPath is the path of the campaign ledger named by the --ledger flag
+func LedgerPath(ctx *util.TASContext) string {
+	filename, err := ctx.Config().Flags.GetString(LedgerFileFlagName)
+	if err != nil || filename == "" {
+		filename = DefaultLedgerFilename
+	}
+	return filepath.Join(defaultLedgerPath, filename)
+}
+
+// LoadLedger reads the campaign ledger named by the --ledger flag from data-local
+func LoadLedger(ctx *util.TASContext) (*model.Ledger, error) {
+	path := LedgerPath(ctx)
+
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("there is no ledger at %s - start one with 'ledger start <world>'", path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	l, err := model.LedgerFromFile(b)
+	if err != nil {
+		return nil, err
+	}
+	if errs := l.Validate(); len(errs) > 0 {
+		return nil, joinErrors(errs)
+	}
+	return l, nil
+}
+
+// SaveLedger writes the ledger to data-local, keeping the old ledger with a .bak extension
+func SaveLedger(ctx *util.TASContext, l *model.Ledger) error {
+	path := LedgerPath(ctx)
+
+	if errs := l.Validate(); len(errs) > 0 {
+		return joinErrors(errs)
+	}
+
+	b, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	existing, err := os.ReadFile(path)
+	if err == nil {
+		if err := os.WriteFile(path+ledgerBackupExt, existing, 0644); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(path, append(b, NL...), 0644)
+}
+
+func joinErrors(errs []error) error {
+	msgs := make([]string, 0, len(errs))
+	for _, e := range errs {
+		msgs = append(msgs, e.Error())
+	}
+	return errors.New(strings.Join(msgs, "; "))
+}
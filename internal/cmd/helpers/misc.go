@@ -49,6 +49,21 @@ func WrappedJSONFileWriter(ctx *util.TASContext, s any, filename string, subtree
 
 	log := ctx.Logger()
 
+	bytes, err := json.MarshalIndent(s, "", " ")
+	if err != nil {
+		log.Error().Err(err).Str("filename", filename).Msg("unable to marshal data to JSON")
+		return
+	}
+
+	WrappedFileWriter(ctx, bytes, filename, subtree...)
+}
+
+// WrappedFileWriter writes already-formatted data (e.g. an SVG map) to a file. The filename and subtree
+// work the same way as for WrappedJSONFileWriter
+func WrappedFileWriter(ctx *util.TASContext, bytes []byte, filename string, subtree ...string) {
+
+	log := ctx.Logger()
+
 	//handle optional creation of deeper output dirs
 	var dirpath string
 	switch len(subtree) {
@@ -67,15 +82,10 @@ func WrappedJSONFileWriter(ctx *util.TASContext, s any, filename string, subtree
 		log.Error().Err(err).Msg("unable to make directory")
 		return
 	}
-	bytes, err := json.MarshalIndent(s, "", " ")
-	if err != nil {
-		log.Error().Err(err).Str("filename", filename).Msg("unable to marshal data to JSON")
-		return
-	}
 
 	//using this approach prevents a file from being created that will overwrite an existing file
 	filePath := filepath.Join(dirpath, filename)
-	f, err := os.OpenFile(filePath, os.O_CREATE|os.O_EXCL, easyAccessFileMode)
+	f, err := os.OpenFile(filePath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, easyAccessFileMode)
 	if err != nil {
 		log.Error().Err(err).Str("filename", filename).Msg("unable to open file")
 		return
@@ -0,0 +1,186 @@
+package ledger
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"tas/internal/cmd/trade"
+	"tas/internal/model"
+	"tas/internal/util"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	loadPassengers = "passengers"
+	loadFreight    = "freight"
+)
+
+var LedgerBuyCmdConfig = &cobra.Command{
+
+	Use:   "buy <goods> <tons> <price-per-ton>",
+	Short: "records speculative cargo bought at the current world",
+	Run: func(cmd *cobra.Command, args []string) {
+		runLedger(cmd, args, false, func(ctx *util.TASContext, l *model.Ledger) (*model.Ledger, string, error) {
+			tons, price, err := intArgs(args[1], args[2])
+			if err != nil {
+				return nil, "", err
+			}
+			if err := l.Buy(args[0], tons, price); err != nil {
+				return nil, "", err
+			}
+			return l, fmt.Sprintf("Bought %dT of %s for Cr%d", tons, args[0], tons*price), nil
+		})
+	},
+
+	Args: cobra.ExactArgs(3),
+}
+
+var LedgerSellCmdConfig = &cobra.Command{
+
+	Use:   "sell <goods> <tons> <price-per-ton>",
+	Short: "records speculative cargo sold at the current world",
+	Run: func(cmd *cobra.Command, args []string) {
+		runLedger(cmd, args, false, func(ctx *util.TASContext, l *model.Ledger) (*model.Ledger, string, error) {
+			tons, price, err := intArgs(args[1], args[2])
+			if err != nil {
+				return nil, "", err
+			}
+			profit, err := l.Sell(args[0], tons, price)
+			if err != nil {
+				return nil, "", err
+			}
+			return l, fmt.Sprintf("Sold %dT of %s for Cr%d, a profit of Cr%d", tons, args[0], tons*price, profit), nil
+		})
+	},
+
+	Args: cobra.ExactArgs(3),
+}
+
+var LedgerLoadCmdConfig = &cobra.Command{
+
+	Use:   "load passengers <high|middle|basic|low> <count> <fare> <destination> | load freight <tons> <payment> <destination>",
+	Short: "records passengers (who pay as they board) or freight (paid on delivery) taken aboard",
+	Run: func(cmd *cobra.Command, args []string) {
+		runLedger(cmd, args, false, func(ctx *util.TASContext, l *model.Ledger) (*model.Ledger, string, error) {
+			if strings.ToLower(args[0]) == loadPassengers {
+				count, fare, err := intArgs(args[2], args[3])
+				if err != nil {
+					return nil, "", err
+				}
+				if err := l.LoadPassengers(args[1], count, fare, args[4]); err != nil {
+					return nil, "", err
+				}
+				return l, fmt.Sprintf("Loaded %d %s passengers to %s for Cr%d", count, args[1], args[4], count*fare), nil
+			}
+
+			tons, payment, err := intArgs(args[1], args[2])
+			if err != nil {
+				return nil, "", err
+			}
+			if err := l.LoadFreight(tons, payment, args[3]); err != nil {
+				return nil, "", err
+			}
+			return l, fmt.Sprintf("Loaded %dT of freight to %s, paying Cr%d on delivery", tons, args[3], payment), nil
+		})
+	},
+
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) > 0 && strings.ToLower(args[0]) == loadPassengers && len(args) == 5 {
+			return nil
+		}
+		if len(args) > 0 && strings.ToLower(args[0]) == loadFreight && len(args) == 4 {
+			return nil
+		}
+		return fmt.Errorf("expected 'passengers <type> <count> <fare> <destination>' or 'freight <tons> <payment> <destination>'")
+	},
+}
+
+var LedgerUnloadCmdConfig = &cobra.Command{
+
+	Use:   "unload",
+	Short: "lets off the passengers and delivers the freight bound for the current world",
+	Run: func(cmd *cobra.Command, args []string) {
+		runLedger(cmd, args, false, func(ctx *util.TASContext, l *model.Ledger) (*model.Ledger, string, error) {
+			before := l.Credits
+			passengers, tons := l.Unload()
+			return l, fmt.Sprintf("Unloaded %d passengers and %dT of freight at %s, paid Cr%d", passengers, tons, l.World, l.Credits-before), nil
+		})
+	},
+
+	Args: cobra.NoArgs,
+}
+
+var LedgerJumpCmdConfig = &cobra.Command{
+
+	Use:   "jump <world>",
+	Short: "moves the ship to another world, moving the date on by the travel time",
+	Run: func(cmd *cobra.Command, args []string) {
+		runLedger(cmd, args, false, func(ctx *util.TASContext, l *model.Ledger) (*model.Ledger, string, error) {
+			travel, err := trade.TripTravelTime(ctx, l.World, args[0])
+			if err != nil {
+				return nil, "", err
+			}
+			if err := l.Jump(args[0], travel.TotalHours); err != nil {
+				return nil, "", err
+			}
+			return l, fmt.Sprintf("Jumped to %s, arriving on %s after %d days", args[0], l.Date, travel.Days()), nil
+		})
+	},
+
+	Args: cobra.ExactArgs(1),
+}
+
+var LedgerWaitCmdConfig = &cobra.Command{
+
+	Use:   "wait <days> [reason]",
+	Short: "moves the date on, such as for the days spent finding a supplier or broker",
+	Run: func(cmd *cobra.Command, args []string) {
+		runLedger(cmd, args, false, func(ctx *util.TASContext, l *model.Ledger) (*model.Ledger, string, error) {
+			days, err := strconv.Atoi(args[0])
+			if err != nil {
+				return nil, "", fmt.Errorf("days must be a whole number, not %s", args[0])
+			}
+			if err := l.Wait(days, strings.Join(args[1:], " ")); err != nil {
+				return nil, "", err
+			}
+			return l, fmt.Sprintf("Waited %d days, until %s", days, l.Date), nil
+		})
+	},
+
+	Args: cobra.MinimumNArgs(1),
+}
+
+var LedgerPayCmdConfig = &cobra.Command{
+
+	Use:   "pay <amount> <item>",
+	Short: "records a cost paid, such as fuel, berthing or the mortgage",
+	Run: func(cmd *cobra.Command, args []string) {
+		runLedger(cmd, args, false, func(ctx *util.TASContext, l *model.Ledger) (*model.Ledger, string, error) {
+			amount, err := strconv.Atoi(args[0])
+			if err != nil {
+				return nil, "", fmt.Errorf("amount must be a number of credits: %s", args[0])
+			}
+			item := strings.Join(args[1:], " ")
+			if err := l.Pay(amount, item); err != nil {
+				return nil, "", err
+			}
+			return l, fmt.Sprintf("Paid Cr%d for %s", amount, item), nil
+		})
+	},
+
+	Args: cobra.MinimumNArgs(2),
+}
+
+func intArgs(a string, b string) (int, int, error) {
+	i, err := strconv.Atoi(a)
+	if err != nil {
+		return 0, 0, fmt.Errorf("expected a whole number, not %s", a)
+	}
+	j, err := strconv.Atoi(b)
+	if err != nil {
+		return 0, 0, fmt.Errorf("expected a whole number, not %s", b)
+	}
+	return i, j, nil
+}
@@ -0,0 +1,183 @@
+package ledger
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	h "tas/internal/cmd/helpers"
+	"tas/internal/model"
+	"tas/internal/util"
+
+	"github.com/spf13/cobra"
+)
+
+/*
+	The ledger keeps the state of a campaign between commands: the world the ship is at, the date, the credit
+	balance and what is aboard. It is read and written by the helpers (see h.LoadLedger) so the trade commands
+	can use it for the current world too
+*/
+
+const (
+	CreditsFlagName = "credits"
+	DateFlagName    = "date"
+
+	DefaultDate = "001-1105"
+
+	historyShown = 10
+)
+
+var LedgerCmdConfig = &cobra.Command{
+
+	Use:   "ledger",
+	Short: "shows the campaign ledger: current world, date, credits and what is aboard",
+	Run:   ledgerCmd,
+
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 0 {
+			return fmt.Errorf("no arguments expected - use one of the ledger sub-commands to change the ledger")
+		}
+		return nil
+	},
+}
+
+var LedgerStartCmdConfig = &cobra.Command{
+
+	Use:   "start <world>",
+	Short: "starts a new campaign ledger at the given world",
+	Run: func(cmd *cobra.Command, args []string) {
+		runLedger(cmd, args, true, func(ctx *util.TASContext, l *model.Ledger) (*model.Ledger, string, error) {
+			credits, _ := ctx.Config().Flags.GetInt(CreditsFlagName)
+			date, _ := ctx.Config().Flags.GetString(DateFlagName)
+			if _, err := model.ParseImperialDate(date); err != nil {
+				return nil, "", err
+			}
+			return model.NewLedger(args[0], date, credits), fmt.Sprintf("Started a ledger at %s with Cr%d", args[0], credits), nil
+		})
+	},
+
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return fmt.Errorf("exactly 1 argument required - the world the campaign starts at")
+		}
+		return nil
+	},
+}
+
+func ledgerCmd(cmd *cobra.Command, args []string) {
+	ctx, ok := newLedgerContext(cmd, args)
+	if !ok {
+		return
+	}
+
+	l, err := h.LoadLedger(ctx)
+	if err != nil {
+		ctx.Logger().Error().Err(err).Msg("unable to read the ledger")
+		return
+	}
+	writeLedgerOutput(ctx, l)
+}
+
+// runLedger loads the ledger, makes a change to it and saves it. A new ledger is only started if isNew is set, and
+// will not replace an existing ledger
+func runLedger(cmd *cobra.Command, args []string, isNew bool, change func(*util.TASContext, *model.Ledger) (*model.Ledger, string, error)) {
+	ctx, ok := newLedgerContext(cmd, args)
+	if !ok {
+		return
+	}
+	log := ctx.Logger()
+
+	var l *model.Ledger
+	if isNew {
+		if _, err := os.Stat(h.LedgerPath(ctx)); err == nil {
+			log.Error().Str("file", h.LedgerPath(ctx)).Msg("a ledger already exists - remove it (or use --ledger to name another file) to start again")
+			return
+		}
+	} else {
+		var err error
+		l, err = h.LoadLedger(ctx)
+		if err != nil {
+			log.Error().Err(err).Msg("unable to read the ledger")
+			return
+		}
+	}
+
+	l, msg, err := change(ctx, l)
+	if err != nil {
+		log.Error().Err(err).Msg("the ledger has not been changed")
+		return
+	}
+
+	if err := h.SaveLedger(ctx, l); err != nil {
+		log.Error().Err(err).Msg("unable to save the ledger")
+		return
+	}
+	fmt.Println(msg)
+	fmt.Printf("Credits: Cr%d\n", l.Credits)
+}
+
+func newLedgerContext(cmd *cobra.Command, args []string) (*util.TASContext, bool) {
+
+	//create a config to hold all data passed into this call
+	cfg, err := util.NewTASConfig().
+		WithArgs(args).
+		WithCmd(cmd)
+	if err != nil {
+		fmt.Println()
+		fmt.Printf("Unable to create config. This is a critical error: %s\n", err)
+		fmt.Println()
+		return nil, false
+	}
+
+	//build a context to make all data easily available between calls
+	loglevel, _ := cfg.Flags.GetString(util.LogLevelFlagName)
+	log := util.NewLogger(loglevel)
+	ctx := util.NewContext().
+		WithLogger(log).
+		WithDice(cfg.Seed()).
+		WithConfig(cfg)
+
+	return ctx, true
+}
+
+func writeLedgerOutput(ctx *util.TASContext, l *model.Ledger) {
+	var sb strings.Builder
+
+	sb.WriteString("Campaign Ledger")
+	sb.WriteString(h.NL + "World:" + h.SP + l.World)
+	sb.WriteString(h.NL + "Date:" + h.SP + l.Date)
+	sb.WriteString(h.NL + "Credits:" + h.SP + fmt.Sprintf("Cr%d", l.Credits))
+
+	sb.WriteString(h.NL)
+	sb.WriteString(h.NL + fmt.Sprintf("Cargo Hold (%dT)", l.CargoTons()))
+	for _, c := range l.Cargo {
+		sb.WriteString(h.NL + h.TAB + fmt.Sprintf("%dT of %s, bought at %s for Cr%d per ton", c.Tons, c.Goods, c.Origin, c.PricePerTon))
+		if c.Date != "" {
+			sb.WriteString(" on " + c.Date)
+		}
+	}
+	for _, f := range l.Freight {
+		sb.WriteString(h.NL + h.TAB + fmt.Sprintf("%dT of freight to %s, Cr%d on delivery", f.Tons, f.Destination, f.Payment))
+	}
+
+	sb.WriteString(h.NL)
+	sb.WriteString(h.NL + "Passengers")
+	for _, p := range l.Passengers {
+		sb.WriteString(h.NL + h.TAB + fmt.Sprintf("%d %s to %s", p.Count, p.PassageType, p.Destination))
+	}
+
+	sb.WriteString(h.NL)
+	sb.WriteString(h.NL + "Recent History")
+	first := h.MaxInt(len(l.History)-historyShown, 0)
+	for _, e := range l.History[first:] {
+		sb.WriteString(h.NL + h.TAB + fmt.Sprintf("%-8s %-12s %-6s %+10d %10d  %s", e.Date, e.World, e.Action, e.Credits, e.Balance, e.Detail))
+	}
+
+	fmt.Println(sb.String())
+
+	//also write to file if requested
+	writeToFile, _ := ctx.Config().Flags.GetBool(util.ToFileFlagName)
+	if writeToFile {
+		h.WrappedJSONFileWriter(ctx, l, l.ToFileName())
+	}
+}
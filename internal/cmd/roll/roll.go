@@ -0,0 +1,138 @@
+package roll
+
+import (
+	"fmt"
+	"strings"
+
+	h "tas/internal/cmd/helpers"
+	"tas/internal/model"
+	"tas/internal/util"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	BoonFlagName  = "boon"
+	BaneFlagName  = "bane"
+	TimesFlagName = "times"
+	DMFlagName    = "dm"
+
+	maxNumberOfRolls = 1000
+)
+
+var RollCmdConfig = &cobra.Command{
+
+	Use:   "roll",
+	Short: "rolls dice using expressions such as 2D-7+DM, D66, D3 or 2D+1 vs 8",
+	Run:   rollCmd,
+
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return fmt.Errorf("exactly 1 argument required - the dice expression to roll (use quotes if it contains spaces)")
+		}
+		if _, err := util.ParseDiceExpression(args[0]); err != nil {
+			return err
+		}
+		return nil
+	},
+}
+
+func rollCmd(cmd *cobra.Command, args []string) {
+
+	//create a config to hold all data passed into this call
+	cfg, err := util.NewTASConfig().
+		WithArgs(args).
+		WithCmd(cmd)
+	if err != nil {
+		fmt.Println()
+		fmt.Printf("Unable to create config. This is a critical error: %s\n", err)
+		fmt.Println()
+		return
+	}
+
+	//build a context to make all data easily available between calls
+	loglevel, _ := cfg.Flags.GetString(util.LogLevelFlagName)
+	log := util.NewLogger(loglevel)
+	ctx := util.NewContext().
+		WithLogger(log).
+		WithDice(cfg.Seed()).
+		WithConfig(cfg)
+
+	//fetch flags
+	boon, _ := cfg.Flags.GetBool(BoonFlagName)
+	bane, _ := cfg.Flags.GetBool(BaneFlagName)
+	times, _ := cfg.Flags.GetInt(TimesFlagName)
+	dm, _ := cfg.Flags.GetInt(DMFlagName)
+
+	if boon && bane {
+		log.Error().Msg("a roll cannot have both a boon and a bane")
+		return
+	}
+	boonBane := util.BoonBaneNone
+	if boon {
+		boonBane = util.Boon
+	}
+	if bane {
+		boonBane = util.Bane
+	}
+
+	if times < 1 || times > maxNumberOfRolls {
+		log.Error().Int("times", times).Int("max", maxNumberOfRolls).Msg("number of rolls is out of range")
+		return
+	}
+
+	//the expression was validated by cobra, so no need to check the error here
+	expr, _ := util.ParseDiceExpression(args[0])
+
+	summary := RollExpression(ctx, expr, dm, boonBane, times)
+	writeOutput(ctx, summary)
+}
+
+func RollExpression(ctx *util.TASContext, expr *util.DiceExpression, dm int, boonBane int, times int) *model.RollSummary {
+
+	log := ctx.Logger()
+	dice := ctx.Dice()
+
+	log.Info().Str("expression", expr.Expression).Int("times", times).Msg("rolling dice...")
+
+	summary := &model.RollSummary{
+		Expression: expr.Expression,
+		DM:         dm,
+		Seed:       dice.Seed(),
+		Rolls:      make([]*util.DiceExpressionResult, 0, times),
+	}
+	switch boonBane {
+	case util.Boon:
+		summary.BoonOrBane = "boon"
+	case util.Bane:
+		summary.BoonOrBane = "bane"
+	}
+
+	for i := 0; i < times; i++ {
+		summary.Rolls = append(summary.Rolls, expr.Roll(dice, dm, boonBane))
+	}
+
+	log.Info().Msg("rolling complete")
+	return summary
+}
+
+func writeOutput(ctx *util.TASContext, summary *model.RollSummary) {
+	var sb strings.Builder
+
+	sb.WriteString("Roll:" + h.SP + summary.Expression)
+	if summary.BoonOrBane != "" {
+		sb.WriteString(h.SP + "with a" + h.SP + summary.BoonOrBane)
+	}
+	sb.WriteString(h.NL + "Seed:" + h.SP + fmt.Sprintf("%d", summary.Seed))
+	for i, r := range summary.Rolls {
+		sb.WriteString(h.NL + h.TAB + fmt.Sprintf("%d:", i+1) + h.SP + r.String())
+	}
+
+	fmt.Println(sb.String())
+
+	//also write to file if requested
+	writeToFile, _ := ctx.Config().Flags.GetBool(util.ToFileFlagName)
+	if writeToFile {
+		h.WrappedJSONFileWriter(ctx, summary, summary.ToFileName())
+	}
+}
@@ -0,0 +1,406 @@
+package route
+
+import (
+	"fmt"
+	"strings"
+
+	h "tas/internal/cmd/helpers"
+	"tas/internal/cmd/sector"
+	"tas/internal/cmd/trade"
+	"tas/internal/cmd/world"
+	"tas/internal/hexgrid"
+	"tas/internal/model"
+	"tas/internal/util"
+
+	"github.com/spf13/cobra"
+)
+
+/*
+	The route planner finds the quickest way between two worlds of a sector for a ship of a given jump rating. It
+	searches the worlds of the sector (jumps to empty hexes are not considered), keeping track of the fuel left in
+	the tanks as parsecs of jumping. The ship can fill its tanks at a world on the route with a starport selling
+	fuel, a gas giant to skim or surface water to draw. Each hop takes the time to move out to the jump limit, an
+	average jump and the time to move in from the next world's jump limit. When refined fuel is preferred, the
+	fewest stops taking on unrefined fuel come before the quickest time
+*/
+
+const (
+	JumpFlagName        = "jump"
+	FuelParsecsFlagName = "fuel-parsecs"
+	RefinedFlagName     = "refined"
+	AvoidRedFlagName    = "avoid-red"
+
+	redZone       = "R"
+	unreached     = -1
+	maxJumpRating = 9
+
+	// when refined fuel is preferred, each stop taking on unrefined fuel costs this many hours in the search, so a
+	// route only uses unrefined fuel if there is no way round it
+	unrefinedPenaltyHours = 100000
+)
+
+var RouteCmdConfig = &cobra.Command{
+
+	Use:   "route <sector-file> <from-world> <to-world>",
+	Short: "plans the quickest route between two worlds of a sector for a ship's jump rating and fuel",
+	Run:   routeCmd,
+
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 3 {
+			return fmt.Errorf("3 arguments required - the sector file (T5 tab-delimited or SEC) and the names or hexes of the source and destination worlds")
+		}
+		return nil
+	},
+}
+
+type routeWorld struct {
+	name       string
+	location   string
+	hex        hexgrid.Hex
+	limitHours int
+	fuel       []string //fuel sources at this world, best first
+	isRed      bool
+}
+
+type routeState struct {
+	world int
+	fuel  int
+}
+
+type routeStep struct {
+	cost      int
+	hours     int
+	unrefined int
+	refuel    string
+	prev      routeState
+}
+
+func routeCmd(cmd *cobra.Command, args []string) {
+
+	//create a config to hold all data passed into this call
+	cfg, err := util.NewTASConfig().
+		WithArgs(args).
+		WithCmd(cmd)
+	if err != nil {
+		fmt.Println()
+		fmt.Printf("Unable to create config. This is a critical error: %s\n", err)
+		fmt.Println()
+		return
+	}
+
+	//build a context to make all data easily available between calls
+	loglevel, _ := cfg.Flags.GetString(util.LogLevelFlagName)
+	log := util.NewLogger(loglevel)
+	ctx := util.NewContext().
+		WithLogger(log).
+		WithDice(cfg.Seed()).
+		WithConfig(cfg)
+
+	//the ship's jump rating and fuel, from the ship profile unless given
+	ship, err := trade.LoadShipProfile(ctx)
+	if err != nil {
+		return
+	}
+	if ship == nil {
+		ship = &model.ShipProfile{JumpRating: 1}
+	}
+	if cfg.Flags.Changed(JumpFlagName) {
+		ship.JumpRating, _ = cfg.Flags.GetInt(JumpFlagName)
+	}
+	fuelParsecs := ship.FuelParsecs()
+	if cfg.Flags.Changed(FuelParsecsFlagName) {
+		fuelParsecs, _ = cfg.Flags.GetInt(FuelParsecsFlagName)
+	}
+	if ship.JumpRating < 1 || ship.JumpRating > maxJumpRating {
+		log.Error().Int("jump-rating", ship.JumpRating).Msg("the jump rating must be 1 to 9")
+		return
+	}
+	if fuelParsecs < 1 {
+		log.Error().Int("fuel-parsecs", fuelParsecs).Msg("the ship must carry fuel for at least 1 parsec")
+		return
+	}
+
+	sec, err := sector.ReadSectorFile(ctx, args[0])
+	if err != nil {
+		log.Error().Err(err).Str("file", args[0]).Msg("unable to read sector file")
+		return
+	}
+	sizes, err := world.LoadWorldSizes(ctx)
+	if err != nil {
+		return
+	}
+	starports, err := world.LoadStarports(ctx)
+	if err != nil {
+		return
+	}
+
+	worlds := routeWorlds(ctx, sec, sizes, starports, ship.Thrust())
+	from, err := findWorld(worlds, args[1])
+	if err != nil {
+		log.Error().Err(err).Msg("unable to find the source world")
+		return
+	}
+	to, err := findWorld(worlds, args[2])
+	if err != nil {
+		log.Error().Err(err).Msg("unable to find the destination world")
+		return
+	}
+
+	refined, _ := cfg.Flags.GetBool(RefinedFlagName)
+	avoidRed, _ := cfg.Flags.GetBool(AvoidRedFlagName)
+
+	itinerary, ok := planRoute(ctx, worlds, from, to, ship.JumpRating, fuelParsecs, refined, avoidRed)
+	if !ok {
+		log.Error().Int("jump-rating", ship.JumpRating).Int("fuel-parsecs", fuelParsecs).Msgf("there is no route from %s to %s", args[1], args[2])
+		return
+	}
+	itinerary.Sector = sec.Name
+	itinerary.Ship = ship.Name
+	itinerary.Thrust = ship.Thrust()
+
+	writeRouteOutput(ctx, itinerary)
+}
+
+// planRoute finds the quickest route between two of the worlds. If refined fuel is preferred, it finds the route
+// with the fewest stops taking on unrefined fuel, then the quickest
+func planRoute(ctx *util.TASContext, worlds []*routeWorld, from int, to int, jumpRating int, fuelParsecs int, refined bool, avoidRed bool) (*model.Itinerary, bool) {
+	log := ctx.Logger()
+
+	itinerary := &model.Itinerary{
+		From:        worlds[from].name,
+		To:          worlds[to].name,
+		JumpRating:  jumpRating,
+		FuelParsecs: fuelParsecs,
+		Refined:     refined,
+		AvoidRed:    avoidRed,
+		Notes:       make([]string, 0),
+	}
+
+	states, end, ok := searchRoute(worlds, from, to, jumpRating, fuelParsecs, refined, avoidRed)
+	if !ok {
+		return nil, false
+	}
+	if unrefined := states[end.world][end.fuel].unrefined; refined && unrefined > 0 {
+		log.Info().Int("stops", unrefined).Msg("no route using only refined fuel")
+		stops := "stops"
+		if unrefined == 1 {
+			stops = "stop"
+		}
+		itinerary.Notes = append(itinerary.Notes, fmt.Sprintf("There is no route using only refined fuel, so unrefined fuel is taken on at %d %s. Unrefined fuel makes a misjump more likely.", unrefined, stops))
+	}
+
+	//walk back from the destination to build the hops in order
+	start := routeState{world: from, fuel: fuelParsecs}
+	path := []routeState{end}
+	for s := end; s != start; {
+		s = states[s.world][s.fuel].prev
+		path = append([]routeState{s}, path...)
+	}
+	for i := 1; i < len(path); i++ {
+		a, b := worlds[path[i-1].world], worlds[path[i].world]
+		step := states[path[i].world][path[i].fuel]
+		hop := &model.ItineraryHop{
+			From:         a.name,
+			FromHex:      a.location,
+			To:           b.name,
+			ToHex:        b.location,
+			Parsecs:      hexgrid.Distance(a.hex, b.hex),
+			Hours:        step.hours - states[path[i-1].world][path[i-1].fuel].hours,
+			ElapsedHours: step.hours,
+		}
+		hop.Refuel = step.refuel
+		itinerary.Hops = append(itinerary.Hops, hop)
+		itinerary.Parsecs += hop.Parsecs
+		itinerary.TotalHours = hop.ElapsedHours
+	}
+
+	if avoidRed {
+		for _, w := range []*routeWorld{worlds[from], worlds[to]} {
+			if w.isRed {
+				itinerary.Notes = append(itinerary.Notes, fmt.Sprintf("%s is a Red Zone.", w.name))
+			}
+		}
+	}
+	itinerary.Notes = append(itinerary.Notes,
+		fmt.Sprintf("Times use an average jump of %d hours; roll 148 + 6D hours for each jump as it is made.", model.AverageJumpHours),
+		"Skimming a gas giant or drawing surface water takes time that is not included.")
+
+	return itinerary, true
+}
+
+// searchRoute is a shortest-time search over the worlds and the fuel left on arriving at each. It returns what it
+// took to reach every state and the state the destination was reached in
+func searchRoute(worlds []*routeWorld, from int, to int, jumpRating int, fuelParsecs int, refined bool, avoidRed bool) ([][]routeStep, routeState, bool) {
+	states := make([][]routeStep, len(worlds))
+	done := make([][]bool, len(worlds))
+	for i := range worlds {
+		states[i] = make([]routeStep, fuelParsecs+1)
+		done[i] = make([]bool, fuelParsecs+1)
+		for f := range states[i] {
+			states[i][f].cost = unreached
+		}
+	}
+
+	//the ship leaves with full tanks
+	states[from][fuelParsecs].cost = 0
+
+	for {
+		//the cheapest state not yet searched from
+		current, best := routeState{}, unreached
+		for i := range states {
+			for f, s := range states[i] {
+				if !done[i][f] && s.cost != unreached && (best == unreached || s.cost < best) {
+					current, best = routeState{world: i, fuel: f}, s.cost
+				}
+			}
+		}
+		if best == unreached {
+			return nil, routeState{}, false
+		}
+		if current.world == to {
+			return states, current, true
+		}
+		done[current.world][current.fuel] = true
+
+		here := states[current.world][current.fuel]
+		a := worlds[current.world]
+		for j, b := range worlds {
+			if j == current.world || (avoidRed && b.isRed && j != to) {
+				continue
+			}
+			parsecs := hexgrid.Distance(a.hex, b.hex)
+			if parsecs > jumpRating || parsecs > current.fuel {
+				continue
+			}
+
+			hours := here.hours + a.limitHours + model.AverageJumpHours + b.limitHours
+			arrive := routeStep{cost: here.cost + hours - here.hours, hours: hours, unrefined: here.unrefined, prev: current}
+			relax(states, done, routeState{world: j, fuel: current.fuel - parsecs}, arrive)
+
+			//or fill the tanks, if there is fuel to be had
+			if len(b.fuel) > 0 && j != to {
+				arrive.refuel = b.fuel[0]
+				if arrive.refuel != model.FuelSourceRefined {
+					arrive.unrefined++
+					if refined {
+						arrive.cost += unrefinedPenaltyHours
+					}
+				}
+				relax(states, done, routeState{world: j, fuel: fuelParsecs}, arrive)
+			}
+		}
+	}
+}
+
+// relax keeps the step if it reaches the state more cheaply than any step so far
+func relax(states [][]routeStep, done [][]bool, state routeState, step routeStep) {
+	s := states[state.world][state.fuel]
+	if !done[state.world][state.fuel] && (s.cost == unreached || step.cost < s.cost) {
+		states[state.world][state.fuel] = step
+	}
+}
+
+// routeWorlds gathers what the search needs to know about each world of the sector
+func routeWorlds(ctx *util.TASContext, sec *model.Sector, sizes model.WorldSizeMap, starports model.WorldStarportMap, thrust int) []*routeWorld {
+	log := ctx.Logger()
+
+	worlds := make([]*routeWorld, 0, len(sec.Worlds))
+	for _, sw := range sec.Worlds {
+		ws := sw.WorldSummaryData
+		hex, err := ws.Hex()
+		if err != nil {
+			log.Warn().Err(err).Str("world", ws.Name).Msg("world has no usable hex location and is left out of the route search")
+			continue
+		}
+		rw := &routeWorld{
+			name:     ws.Name,
+			location: ws.HexLocation,
+			hex:      hex,
+			isRed:    strings.EqualFold(ws.TravelZone, redZone),
+			fuel:     make([]string, 0),
+		}
+		if size, err := util.EHexAsInt(ws.Size); err == nil {
+			if s, ok := sizes[size]; ok {
+				rw.limitHours = model.JumpLimitHours(s.DiameterKm(), thrust)
+			}
+		}
+
+		if sp, ok := starports.ByCode(ws.Starport); ok {
+			if sp.HasRefinedFuel() {
+				rw.fuel = append(rw.fuel, model.FuelSourceRefined)
+			}
+			if sp.HasUnrefinedFuel() {
+				rw.fuel = append(rw.fuel, model.FuelSourceUnrefined)
+			}
+		}
+		if sw.HasGasGiant() {
+			rw.fuel = append(rw.fuel, model.FuelSourceGasGiant)
+		}
+		if hydro, err := util.EHexAsInt(ws.Hydrographics); err == nil && hydro > 0 {
+			rw.fuel = append(rw.fuel, model.FuelSourceWater)
+		}
+		worlds = append(worlds, rw)
+	}
+	return worlds
+}
+
+// findWorld finds a world by its hex (e.g. 0304) or its name, ignoring case
+func findWorld(worlds []*routeWorld, nameOrHex string) (int, error) {
+	found := make([]int, 0)
+	for i, w := range worlds {
+		if w.location == nameOrHex || strings.EqualFold(w.name, nameOrHex) {
+			found = append(found, i)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return 0, fmt.Errorf("there is no world named %s, or in hex %s, in the sector", nameOrHex, nameOrHex)
+	case 1:
+		return found[0], nil
+	}
+
+	hexes := make([]string, len(found))
+	for i, f := range found {
+		hexes[i] = worlds[f].location
+	}
+	return 0, fmt.Errorf("there is more than one world named %s (in hexes %s) - use its hex instead", nameOrHex, strings.Join(hexes, ", "))
+}
+
+func writeRouteOutput(ctx *util.TASContext, itinerary *model.Itinerary) {
+	var sb strings.Builder
+
+	sb.WriteString("Route from " + itinerary.From + " to " + itinerary.To + " in " + itinerary.Sector)
+	if itinerary.Ship != "" {
+		sb.WriteString(h.NL + "Ship: " + itinerary.Ship)
+	}
+	sb.WriteString(h.NL + fmt.Sprintf("Jump-%d with fuel for %d parsecs at %dG", itinerary.JumpRating, itinerary.FuelParsecs, itinerary.Thrust))
+	if itinerary.Refined {
+		sb.WriteString(", preferring refined fuel")
+	}
+	if itinerary.AvoidRed {
+		sb.WriteString(", avoiding Red Zones")
+	}
+
+	sb.WriteString(h.NL)
+	for i, hop := range itinerary.Hops {
+		sb.WriteString(h.NL + h.TAB + fmt.Sprintf("%d. %s (%s) to %s (%s), %d parsecs, %d hours - day %d",
+			i+1, hop.From, hop.FromHex, hop.To, hop.ToHex, hop.Parsecs, hop.Hours, model.HoursAsDays(hop.ElapsedHours)))
+		if hop.Refuel != "" {
+			sb.WriteString(", refuel: " + hop.Refuel)
+		}
+	}
+
+	sb.WriteString(h.NL)
+	sb.WriteString(h.NL + fmt.Sprintf("Total: %d jumps, %d parsecs, %d days (%d hours)", len(itinerary.Hops), itinerary.Parsecs, model.HoursAsDays(itinerary.TotalHours), itinerary.TotalHours))
+	for _, n := range itinerary.Notes {
+		sb.WriteString(h.NL + h.TAB + n)
+	}
+
+	fmt.Println(sb.String())
+
+	//also write to file if requested
+	writeToFile, _ := ctx.Config().Flags.GetBool(util.ToFileFlagName)
+	if writeToFile {
+		h.WrappedJSONFileWriter(ctx, itinerary, itinerary.ToFileName())
+	}
+}
@@ -0,0 +1,122 @@
+package route
+
+import (
+	"testing"
+
+	"tas/internal/hexgrid"
+	"tas/internal/model"
+	"tas/internal/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testWorld is a world in column 01, so the parsecs between two of them is the difference in their rows
+func testWorld(t *testing.T, name string, location string, isRed bool, fuel ...string) *routeWorld {
+	hex, err := hexgrid.Parse(location)
+	assert.NoError(t, err)
+	return &routeWorld{name: name, location: location, hex: hex, isRed: isRed, fuel: fuel}
+}
+
+func hopNames(itinerary *model.Itinerary) []string {
+	names := make([]string, 0, len(itinerary.Hops))
+	for _, h := range itinerary.Hops {
+		names = append(names, h.To)
+	}
+	return names
+}
+
+func TestPlanRoute(t *testing.T) {
+
+	ctx := util.NewContext().WithLogger(util.NewLogger("off"))
+
+	worlds := []*routeWorld{
+		testWorld(t, "Start", "0101", false),
+		testWorld(t, "Refined A", "0102", false, model.FuelSourceRefined),
+		testWorld(t, "Giant", "0103", false, model.FuelSourceGasGiant),
+		testWorld(t, "Refined B", "0104", false, model.FuelSourceRefined),
+		testWorld(t, "End", "0105", false),
+	}
+
+	//the destination is 4 parsecs away, so a jump-2 ship with fuel for 2 parsecs has to refuel on the way
+	itinerary, ok := planRoute(ctx, worlds, 0, 4, 2, 2, false, false)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"Giant", "End"}, hopNames(itinerary))
+	assert.Equal(t, model.FuelSourceGasGiant, itinerary.Hops[0].Refuel)
+	assert.Equal(t, 4, itinerary.Parsecs)
+	assert.Equal(t, 2*model.AverageJumpHours, itinerary.TotalHours)
+
+	//preferring refined fuel takes the longer way round
+	itinerary, ok = planRoute(ctx, worlds, 0, 4, 2, 2, true, false)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"Refined A", "Refined B", "End"}, hopNames(itinerary))
+	for _, h := range itinerary.Hops[:2] {
+		assert.Equal(t, model.FuelSourceRefined, h.Refuel)
+	}
+
+	//the trip is the same world
+	itinerary, ok = planRoute(ctx, worlds, 0, 0, 2, 2, false, false)
+	assert.True(t, ok)
+	assert.Empty(t, itinerary.Hops)
+	assert.Equal(t, 0, itinerary.TotalHours)
+}
+
+func TestPlanRouteOnlyUnrefined(t *testing.T) {
+
+	ctx := util.NewContext().WithLogger(util.NewLogger("off"))
+
+	worlds := []*routeWorld{
+		testWorld(t, "Start", "0101", false),
+		testWorld(t, "Giant", "0103", false, model.FuelSourceGasGiant),
+		testWorld(t, "End", "0105", false),
+	}
+
+	//refined fuel is preferred, but there is none to be had
+	itinerary, ok := planRoute(ctx, worlds, 0, 2, 2, 2, true, false)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"Giant", "End"}, hopNames(itinerary))
+	assert.Equal(t, model.FuelSourceGasGiant, itinerary.Hops[0].Refuel)
+	assert.Contains(t, itinerary.Notes[0], "no route using only refined fuel")
+}
+
+func TestPlanRouteAvoidRed(t *testing.T) {
+
+	ctx := util.NewContext().WithLogger(util.NewLogger("off"))
+
+	worlds := []*routeWorld{
+		testWorld(t, "Start", "0101", false),
+		testWorld(t, "Red", "0103", true, model.FuelSourceRefined),
+		testWorld(t, "Green", "0104", false, model.FuelSourceRefined),
+		testWorld(t, "End", "0106", false),
+	}
+
+	//fuel for only 3 parsecs means a stop is needed, and either world will do
+	itinerary, ok := planRoute(ctx, worlds, 0, 3, 3, 3, false, false)
+	assert.True(t, ok)
+	assert.Len(t, itinerary.Hops, 2)
+
+	itinerary, ok = planRoute(ctx, worlds, 0, 3, 3, 3, false, true)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"Green", "End"}, hopNames(itinerary))
+
+	//with only the Red Zone to stop at, there is no way through
+	_, ok = planRoute(ctx, worlds[:2], 0, 1, 3, 3, false, true)
+	assert.True(t, ok, "a Red Zone destination can still be reached")
+	worlds = []*routeWorld{worlds[0], worlds[1], worlds[3]}
+	_, ok = planRoute(ctx, worlds, 0, 2, 3, 3, false, true)
+	assert.False(t, ok)
+}
+
+func TestPlanRouteNoRoute(t *testing.T) {
+
+	ctx := util.NewContext().WithLogger(util.NewLogger("off"))
+
+	worlds := []*routeWorld{
+		testWorld(t, "Start", "0101", false),
+		testWorld(t, "Giant", "0103", false, model.FuelSourceGasGiant),
+		testWorld(t, "End", "0110", false),
+	}
+
+	//the gap from the gas giant to the destination is longer than the ship can jump
+	_, ok := planRoute(ctx, worlds, 0, 2, 2, 2, false, false)
+	assert.False(t, ok)
+}
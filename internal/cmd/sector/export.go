@@ -0,0 +1,236 @@
+package sector
+
+import (
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strings"
+
+	h "tas/internal/cmd/helpers"
+	"tas/internal/model"
+	"tas/internal/util"
+)
+
+/*
+	Sector exports use the formats read by the travellermap.com poster tools (and most other Traveller
+	utilities): the T5 tab-delimited format, or the older fixed-column SEC format. Both are written along
+	with a travellermap metadata XML file holding the sector name, subsector names and routes
+*/
+
+const (
+	FormatT5Tab = "t5tab"
+	FormatSec   = "sec"
+
+	//generated worlds are not aligned to any polity
+	defaultAllegiance = "Na"
+
+	//the SEC name and remarks columns are fixed width, so anything longer is cut
+	secNameWidth    = 20
+	secRemarksWidth = 20
+)
+
+var t5TabColumns = []string{"Hex", "Name", "UWP", "Bases", "Remarks", "Zone", "PBG", "Allegiance", "Stars", "{Ix}", "(Ex)", "[Cx]"}
+
+// the SEC format has one column for bases, so combinations use a single letter
+var secBaseCodes = map[string]string{"N": "N", "S": "S", "M": "M", "C": "C", "D": "D", "W": "W", "NS": "A", "NW": "B", "MN": "F"}
+
+// validExportFormat is true if the format is one that can be exported (or no format at all)
+func validExportFormat(format string) bool {
+	switch format {
+	case "", FormatT5Tab, FormatSec:
+		return true
+	}
+	return false
+}
+
+func exportSector(ctx *util.TASContext, sector *model.Sector, format string) {
+
+	var data []byte
+	var ext string
+
+	switch format {
+	case FormatT5Tab:
+		data, ext = SectorAsT5Tab(sector), ".tab"
+	case FormatSec:
+		data, ext = SectorAsSec(sector), ".sec"
+		warnSecTruncation(ctx, sector)
+	default:
+		return
+	}
+
+	metadata, err := SectorMetadataXML(sector)
+	if err != nil {
+		ctx.Logger().Error().Err(err).Msg("unable to create sector metadata")
+		return
+	}
+
+	folder := sector.ToFileName()
+	h.WrappedFileWriter(ctx, data, folder+ext, folder)
+	h.WrappedFileWriter(ctx, metadata, folder+".xml", folder)
+}
+
+// SectorAsT5Tab writes the sector in the T5 tab-delimited format, one world per line after a header line
+func SectorAsT5Tab(sector *model.Sector) []byte {
+	var sb strings.Builder
+
+	sb.WriteString(strings.Join(t5TabColumns, h.TAB) + h.NL)
+	for _, sw := range sector.Worlds {
+		w := sw.WorldSummaryData
+		cols := []string{
+			w.HexLocation,
+			w.Name,
+			coreUWP(w),
+			strings.Join(w.Bases, ""),
+			strings.Join(exportTradeCodes(w), h.SP),
+			exportZone(w),
+			w.PBG,
+			exportAllegiance(w),
+			w.Stars,
+			w.Importance,
+			w.Economic,
+			w.Cultural,
+		}
+		sb.WriteString(strings.Join(cols, h.TAB) + h.NL)
+	}
+	return []byte(sb.String())
+}
+
+// SectorAsSec writes the sector in the older fixed-column SEC format
+func SectorAsSec(sector *model.Sector) []byte {
+	var sb strings.Builder
+
+	sb.WriteString("# " + sector.Name + h.NL)
+	sb.WriteString(fmt.Sprintf("# Generated with seed %d", sector.Seed) + h.NL)
+	for _, ss := range sector.Subsectors {
+		sb.WriteString(fmt.Sprintf("# Subsector %s: %s", ss.Letter, ss.Name) + h.NL)
+	}
+	sb.WriteString("#" + h.NL)
+	sb.WriteString("#Name                Hex  UWP       B Remarks              Z PBG Al Stars" + h.NL)
+	sb.WriteString("#------------------- ---- --------- - -------------------- - --- -- ---------------" + h.NL)
+	for _, sw := range sector.Worlds {
+		w := sw.WorldSummaryData
+		zone := exportZone(w)
+		if zone == "" {
+			zone = h.SP
+		}
+		sb.WriteString(fmt.Sprintf("%-*.*s %s %s %s %-*.*s %s %s %s %s",
+			secNameWidth, secNameWidth, w.Name, w.HexLocation, coreUWP(w), secBase(w.Bases), secRemarksWidth, secRemarksWidth, strings.Join(exportTradeCodes(w), h.SP), zone, w.PBG, exportAllegiance(w), w.Stars))
+		sb.WriteString(h.NL)
+	}
+	return []byte(sb.String())
+}
+
+// warnSecTruncation logs the worlds whose name or remarks are too long for the SEC columns, as they are cut short
+// in the export
+func warnSecTruncation(ctx *util.TASContext, sector *model.Sector) {
+	log := ctx.Logger()
+	for _, sw := range sector.Worlds {
+		w := sw.WorldSummaryData
+		if len(w.Name) > secNameWidth {
+			log.Warn().Str("hex", w.HexLocation).Str("name", w.Name).Msgf("the world's name is cut to %d characters in the SEC export", secNameWidth)
+		}
+		if remarks := strings.Join(exportTradeCodes(w), h.SP); len(remarks) > secRemarksWidth {
+			log.Warn().Str("hex", w.HexLocation).Str("remarks", remarks).Msgf("the world's remarks are cut to %d characters in the SEC export - use the t5tab format to keep them all", secRemarksWidth)
+		}
+	}
+}
+
+type xmlSector struct {
+	XMLName    xml.Name       `xml:"Sector"`
+	Name       string         `xml:"Name"`
+	Subsectors []xmlSubsector `xml:"Subsectors>Subsector"`
+	Routes     []xmlRoute     `xml:"Routes>Route"`
+}
+
+type xmlSubsector struct {
+	Index string `xml:"Index,attr"`
+	Name  string `xml:",chardata"`
+}
+
+type xmlRoute struct {
+	Start string `xml:"Start,attr"`
+	End   string `xml:"End,attr"`
+	Type  string `xml:"Type,attr"`
+	Style string `xml:"Style,attr,omitempty"`
+}
+
+// SectorMetadataXML writes the sector name, subsector names and routes as travellermap sector metadata
+func SectorMetadataXML(sector *model.Sector) ([]byte, error) {
+	meta := xmlSector{
+		Name:       sector.Name,
+		Subsectors: make([]xmlSubsector, 0, len(sector.Subsectors)),
+		Routes:     make([]xmlRoute, 0, len(sector.Routes)),
+	}
+	for _, ss := range sector.Subsectors {
+		meta.Subsectors = append(meta.Subsectors, xmlSubsector{Index: ss.Letter, Name: ss.Name})
+	}
+	for _, r := range sector.Routes {
+		route := xmlRoute{Start: r.From, End: r.To, Type: "Trade", Style: "Dashed"}
+		if r.Type == model.RouteTypeXboat {
+			route.Type = "Xboat"
+			route.Style = ""
+		}
+		meta.Routes = append(meta.Routes, route)
+	}
+
+	b, err := xml.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return []byte(xml.Header + string(b) + h.NL), nil
+}
+
+func coreUWP(w *model.WorldSummary) string {
+	return w.Starport + w.Size + w.Atmosphere + w.Hydrographics + w.Population + w.Government + w.LawLevel + "-" + w.TechLevel
+}
+
+// trade codes are written as in published data e.g. Ag Ni
+func exportTradeCodes(w *model.WorldSummary) []string {
+	codes := make([]string, 0, len(w.TradeCodes))
+	for _, c := range w.TradeCodes {
+		codes = append(codes, c[0:1]+strings.ToLower(c[1:]))
+	}
+	return codes
+}
+
+// imported worlds keep their allegiance
+func exportAllegiance(w *model.WorldSummary) string {
+	if w.Allegiance != "" {
+		return w.Allegiance
+	}
+	return defaultAllegiance
+}
+
+// green zones are left blank
+func exportZone(w *model.WorldSummary) string {
+	if w.TravelZone == "A" || w.TravelZone == "R" {
+		return w.TravelZone
+	}
+	return ""
+}
+
+func secBase(bases []string) string {
+	if len(bases) == 0 {
+		return h.SP
+	}
+	sorted := append([]string{}, bases...)
+	sort.Strings(sorted)
+	if code, ok := secBaseCodes[strings.Join(sorted, "")]; ok {
+		return code
+	}
+	//no single letter covers this combination, so the naval and scout bases are kept as they matter most to travellers
+	hasNaval, hasScout := false, false
+	for _, b := range bases {
+		hasNaval = hasNaval || b == "N"
+		hasScout = hasScout || b == "S"
+	}
+	switch {
+	case hasNaval && hasScout:
+		return "A"
+	case hasNaval:
+		return "N"
+	case hasScout:
+		return "S"
+	}
+	return bases[0]
+}
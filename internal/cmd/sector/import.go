@@ -0,0 +1,484 @@
+package sector
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	h "tas/internal/cmd/helpers"
+	"tas/internal/cmd/world"
+	"tas/internal/hexgrid"
+	"tas/internal/model"
+	"tas/internal/util"
+
+	"github.com/spf13/cobra"
+)
+
+/*
+	Published sectors are read from the same formats they are exported to (see export.go): the T5
+	tab-delimited format, recognised by its header line, or the older fixed-column SEC format. Each row's UWP
+	goes through the world parser, so an imported world is described just like a generated one. Rows that
+	cannot be read are skipped and reported by line number. If a travellermap metadata file with the same
+	name (but an .xml extension) sits alongside, its sector name, subsector names and routes are used
+*/
+
+const (
+	diagnosticError   = "error"
+	diagnosticWarning = "warning"
+)
+
+// a SEC row is anchored on its hex and UWP - the name is before them, everything else after
+var secRowRegEx = regexp.MustCompile(`^(.*?)\s*([0-9]{4})\s+([A-Z][0-9A-Z]{6}-[0-9A-Z])(.*)$`)
+
+// comments in our own SEC exports (and many others) name the subsectors
+var secSubsectorRegEx = regexp.MustCompile(`^#\s*Subsector\s+([A-P])\s*:\s*(.+)$`)
+
+var pbgRegEx = regexp.MustCompile(`^[0-9A-Z]{3}$`)
+
+var SectorImportCmdConfig = &cobra.Command{
+
+	Use:   "import <file> [sector-name]",
+	Short: "reads a published sector file (T5 tab-delimited or SEC) so its worlds can be described, mapped, exported and traded with",
+	Run:   importSectorCmd,
+
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 || len(args) > 2 {
+			return fmt.Errorf("the sector file is required and may be followed by the name of the sector")
+		}
+		return nil
+	},
+}
+
+type importDiagnostic struct {
+	line     int
+	severity string
+	msg      string
+}
+
+// importRow holds the columns of one world in a sector file
+type importRow struct {
+	line       int
+	hex        string
+	name       string
+	uwp        string
+	bases      string
+	remarks    string
+	zone       string
+	pbg        string
+	allegiance string
+	stars      string
+	extensions []string
+}
+
+func importSectorCmd(cmd *cobra.Command, args []string) {
+
+	//create a config to hold all data passed into this call
+	cfg, err := util.NewTASConfig().
+		WithArgs(args).
+		WithCmd(cmd)
+	if err != nil {
+		fmt.Println()
+		fmt.Printf("Unable to create config. This is a critical error: %s\n", err)
+		fmt.Println()
+		return
+	}
+
+	//build a context to make all data easily available between calls
+	loglevel, _ := cfg.Flags.GetString(util.LogLevelFlagName)
+	log := util.NewLogger(loglevel)
+	ctx := util.NewContext().
+		WithLogger(log).
+		WithDice(cfg.Seed()).
+		WithConfig(cfg)
+
+	//load the data we need to interpret & output a world
+	src, err := world.LoadWorldSourceData(ctx)
+	if err != nil {
+		return
+	}
+
+	//the sector is named by the argument, if given
+	name := ""
+	if len(args) > 1 {
+		name = args[1]
+	}
+	sector, diagnostics, err := readSectorFile(ctx, src, args[0], name)
+	if err != nil {
+		log.Error().Err(err).Str("file", args[0]).Msg("unable to read sector file")
+		return
+	}
+
+	writeDiagnostics(args[0], len(sector.Worlds), diagnostics)
+	writeSector(ctx, sector, nil)
+}
+
+// ReadSectorFile reads a published sector for commands that work over its worlds. Rows that cannot be read are
+// left out of the sector with a warning
+func ReadSectorFile(ctx *util.TASContext, filename string) (*model.Sector, error) {
+	log := ctx.Logger()
+
+	src, err := world.LoadWorldSourceData(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sector, diagnostics, err := readSectorFile(ctx, src, filename, "")
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range diagnostics {
+		if d.severity == diagnosticError {
+			log.Warn().Int("line", d.line).Str("file", filename).Msg(d.msg)
+		}
+	}
+	return sector, nil
+}
+
+// readSectorFile reads the sector in a T5 tab-delimited or SEC file along with its metadata file, if there is one.
+// The sector is named by the name given, then the metadata file, then the file itself
+func readSectorFile(ctx *util.TASContext, src *model.WorldSource, filename string, name string) (*model.Sector, []importDiagnostic, error) {
+	log := ctx.Logger()
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sector, diagnostics := importSector(ctx, src, string(data))
+
+	sector.Name = strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+	metadataFile := strings.TrimSuffix(filename, filepath.Ext(filename)) + ".xml"
+	haveRoutes := false
+	if metadata, err := os.ReadFile(metadataFile); err == nil {
+		haveRoutes, err = applySectorMetadata(sector, metadata)
+		if err != nil {
+			log.Warn().Err(err).Str("file", metadataFile).Msg("unable to read sector metadata, subsector names and routes are left out")
+		}
+	}
+	if name != "" {
+		sector.Name = name
+	}
+
+	//a sector without published routes gets generated ones
+	if !haveRoutes {
+		sector.Routes = generateRoutes(ctx, sector)
+	}
+
+	return sector, diagnostics, nil
+}
+
+// importSector reads the worlds in the text of a T5 tab-delimited or SEC sector file. Rows that cannot be read
+// are left out of the sector and reported by line number, along with any data that was ignored
+func importSector(ctx *util.TASContext, src *model.WorldSource, data string) (*model.Sector, []importDiagnostic) {
+
+	sector := &model.Sector{
+		Name:       "unknown",
+		Seed:       ctx.Dice().Seed(),
+		Subsectors: make([]*model.Subsector, 0, len(model.SubsectorLetters())),
+		Worlds:     make([]*model.SectorWorld, 0),
+		Routes:     make([]*model.Route, 0),
+	}
+	for _, letter := range model.SubsectorLetters() {
+		sector.Subsectors = append(sector.Subsectors, &model.Subsector{Letter: letter, Name: "Subsector " + letter})
+	}
+
+	lines := strings.Split(strings.ReplaceAll(data, "\r\n", h.NL), h.NL)
+	var rows []*importRow
+	var diagnostics []importDiagnostic
+	if isT5Tab(lines) {
+		rows, diagnostics = readT5TabRows(lines)
+	} else {
+		rows, diagnostics = readSecRows(sector, lines)
+	}
+
+	seen := make(map[string]int)
+	for _, row := range rows {
+		if first, ok := seen[row.hex]; ok {
+			diagnostics = append(diagnostics, importDiagnostic{row.line, diagnosticError, fmt.Sprintf("hex %s is already used on line %d", row.hex, first)})
+			continue
+		}
+		sw, warnings, err := importWorld(ctx, src, row)
+		for _, w := range warnings {
+			diagnostics = append(diagnostics, importDiagnostic{row.line, diagnosticWarning, w})
+		}
+		if err != nil {
+			diagnostics = append(diagnostics, importDiagnostic{row.line, diagnosticError, err.Error()})
+			continue
+		}
+		seen[row.hex] = row.line
+		sector.Worlds = append(sector.Worlds, sw)
+	}
+
+	sort.SliceStable(sector.Worlds, func(i, j int) bool {
+		return sector.Worlds[i].WorldSummaryData.HexLocation < sector.Worlds[j].WorldSummaryData.HexLocation
+	})
+	sort.SliceStable(diagnostics, func(i, j int) bool {
+		return diagnostics[i].line < diagnostics[j].line
+	})
+	return sector, diagnostics
+}
+
+// a T5 tab-delimited file starts (after any comments) with a tab separated header line
+func isT5Tab(lines []string) bool {
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		return strings.Contains(line, h.TAB)
+	}
+	return false
+}
+
+func readT5TabRows(lines []string) ([]*importRow, []importDiagnostic) {
+
+	rows := make([]*importRow, 0)
+	diagnostics := make([]importDiagnostic, 0)
+
+	var columns map[string]int
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, h.TAB)
+
+		//the first line is the header, which names the columns
+		if columns == nil {
+			columns = make(map[string]int)
+			for c, name := range fields {
+				columns[strings.ToLower(strings.TrimSpace(name))] = c
+			}
+			for _, required := range []string{"hex", "uwp"} {
+				if _, ok := columns[required]; !ok {
+					diagnostics = append(diagnostics, importDiagnostic{i + 1, diagnosticError, "the header has no " + required + " column, so no worlds can be read"})
+					return rows, diagnostics
+				}
+			}
+			continue
+		}
+
+		column := func(name string) string {
+			if c, ok := columns[name]; ok && c < len(fields) {
+				return strings.TrimSpace(fields[c])
+			}
+			return ""
+		}
+		row := &importRow{
+			line:       i + 1,
+			hex:        column("hex"),
+			name:       column("name"),
+			uwp:        column("uwp"),
+			bases:      column("bases"),
+			remarks:    column("remarks"),
+			zone:       column("zone"),
+			pbg:        column("pbg"),
+			allegiance: column("allegiance"),
+			stars:      column("stars"),
+		}
+		for _, ext := range []string{"{ix}", "(ex)", "[cx]"} {
+			if v := column(ext); v != "" && v != "-" {
+				row.extensions = append(row.extensions, v)
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	if columns == nil {
+		diagnostics = append(diagnostics, importDiagnostic{0, diagnosticError, "the file has no header line"})
+	}
+	return rows, diagnostics
+}
+
+func readSecRows(sector *model.Sector, lines []string) ([]*importRow, []importDiagnostic) {
+
+	rows := make([]*importRow, 0)
+	diagnostics := make([]importDiagnostic, 0)
+
+	for i, line := range lines {
+		if m := secSubsectorRegEx.FindStringSubmatch(line); m != nil {
+			for _, ss := range sector.Subsectors {
+				if ss.Letter == m[1] {
+					ss.Name = strings.TrimSpace(m[2])
+				}
+			}
+			continue
+		}
+		if strings.TrimSpace(strings.Trim(line, "-")) == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "$") || strings.HasPrefix(line, "@") {
+			continue
+		}
+
+		m := secRowRegEx.FindStringSubmatch(strings.TrimRight(line, " "))
+		if m == nil {
+			diagnostics = append(diagnostics, importDiagnostic{i + 1, diagnosticError, "no hex and UWP (e.g. 0101 CA6A643-9) were found"})
+			continue
+		}
+		row := &importRow{line: i + 1, name: strings.TrimSpace(m[1]), hex: m[2], uwp: m[3]}
+
+		//the base is the single letter straight after the UWP, if there is one
+		rest := m[4]
+		if len(rest) >= 2 && rest[0] == ' ' && rest[1] != ' ' && (len(rest) == 2 || rest[2] == ' ') {
+			row.bases = rest[1:2]
+			rest = rest[2:]
+		}
+
+		//remarks and zone come before the PBG, allegiance and stars after it
+		tokens := strings.Fields(rest)
+		pbgIdx := -1
+		for t, token := range tokens {
+			if pbgRegEx.MatchString(token) {
+				pbgIdx = t
+				break
+			}
+		}
+		remarks := tokens
+		if pbgIdx >= 0 {
+			remarks = tokens[:pbgIdx]
+			row.pbg = tokens[pbgIdx]
+			if pbgIdx+1 < len(tokens) {
+				row.allegiance = tokens[pbgIdx+1]
+				row.stars = strings.Join(tokens[pbgIdx+2:], h.SP)
+			}
+		}
+		if n := len(remarks); n > 0 && len(remarks[n-1]) == 1 {
+			row.zone = remarks[n-1]
+			remarks = remarks[:n-1]
+		}
+		row.remarks = strings.Join(remarks, h.SP)
+		rows = append(rows, row)
+	}
+	return rows, diagnostics
+}
+
+// importWorld turns a row of a sector file into a sector world. Problems with the UWP or hex mean the world
+// cannot be used, while problems with the other columns only leave that data out
+func importWorld(ctx *util.TASContext, src *model.WorldSource, row *importRow) (*model.SectorWorld, []string, error) {
+
+	log := ctx.Logger()
+	warnings := make([]string, 0)
+
+	col, r, err := model.ParseHexID(row.hex)
+	if err != nil {
+		return nil, warnings, err
+	}
+
+	//only the trade codes we know are kept, as published data has many other remarks
+	tokens := []string{row.uwp}
+	known := make(map[string]struct{})
+	for _, remark := range strings.Fields(row.remarks) {
+		tc, ok := src.WorldTradeCodes.ByCode(remark)
+		if !ok {
+			log.Debug().Int("line", row.line).Str("remark", remark).Msg("remark is not a known trade code and is ignored")
+			continue
+		}
+		if _, dupe := known[tc.Code]; !dupe {
+			known[tc.Code] = struct{}{}
+			tokens = append(tokens, tc.Code)
+		}
+	}
+	if zone := strings.ToUpper(row.zone); zone == "A" || zone == "R" {
+		tokens = append(tokens, zone)
+	}
+	tokens = append(tokens, row.extensions...)
+
+	def, err := world.ParseWorldUWP(strings.Join(tokens, h.SP), src)
+	if err != nil {
+		return nil, warnings, err
+	}
+	def.Name = row.name
+	def.SubsectorLoc = row.hex
+
+	bases, err := model.ParseBaseCodes(row.bases)
+	if err != nil {
+		warnings = append(warnings, err.Error()+", the world's bases are left out")
+	} else {
+		def.Bases = bases
+	}
+
+	if row.pbg != "" || row.stars != "" {
+		sys, err := model.ParseStarSystem(row.pbg, row.stars)
+		if err != nil {
+			warnings = append(warnings, err.Error()+", the world's star system is left out")
+		} else {
+			def.System = sys
+		}
+	}
+
+	summary, err := world.GenerateWorldSummary(ctx, def, src)
+	if err != nil {
+		return nil, warnings, err
+	}
+	if def.System == nil {
+		//keep what the file says even if it could not be understood
+		summary.PBG = row.pbg
+		summary.Stars = row.stars
+	}
+	if row.allegiance != "" && row.allegiance != "-" {
+		summary.Allegiance = row.allegiance
+	}
+	summary.UWP = summary.ToUWP(false)
+	world.BuildLongDescription(ctx, summary)
+
+	sw := &model.SectorWorld{
+		WorldSummaryData: summary,
+		Subsector:        model.SubsectorForHex(col, r),
+	}
+	return sw, warnings, nil
+}
+
+// applySectorMetadata takes the sector name, subsector names and routes from travellermap metadata. It is true
+// if the metadata lists any routes
+func applySectorMetadata(sector *model.Sector, data []byte) (bool, error) {
+
+	var meta xmlSector
+	if err := xml.Unmarshal(data, &meta); err != nil {
+		return false, err
+	}
+
+	if meta.Name != "" {
+		sector.Name = meta.Name
+	}
+	for _, mss := range meta.Subsectors {
+		for _, ss := range sector.Subsectors {
+			if ss.Letter == strings.ToUpper(mss.Index) && strings.TrimSpace(mss.Name) != "" {
+				ss.Name = strings.TrimSpace(mss.Name)
+			}
+		}
+	}
+
+	errs := make([]string, 0)
+	for _, mr := range meta.Routes {
+		from, errFrom := hexgrid.Parse(mr.Start)
+		to, errTo := hexgrid.Parse(mr.End)
+		if errFrom != nil || errTo != nil {
+			errs = append(errs, fmt.Sprintf("route %s-%s", mr.Start, mr.End))
+			continue
+		}
+		route := &model.Route{Type: model.RouteTypeTrade, From: mr.Start, To: mr.End, Parsecs: hexgrid.Distance(from, to)}
+		if strings.EqualFold(mr.Type, "xboat") {
+			route.Type = model.RouteTypeXboat
+		}
+		sector.Routes = append(sector.Routes, route)
+	}
+	if len(errs) > 0 {
+		return len(sector.Routes) > 0, errors.New("unable to read " + strings.Join(errs, ", "))
+	}
+	return len(sector.Routes) > 0, nil
+}
+
+func writeDiagnostics(filename string, imported int, diagnostics []importDiagnostic) {
+
+	var sb strings.Builder
+
+	sb.WriteString(h.NL + fmt.Sprintf("Imported %d worlds from %s", imported, filename))
+	if len(diagnostics) > 0 {
+		sb.WriteString(h.NL + "-------------------------------------")
+	}
+	for _, d := range diagnostics {
+		sb.WriteString(h.NL + fmt.Sprintf("line %d: %s: %s", d.line, d.severity, d.msg))
+	}
+	fmt.Println(sb.String())
+}
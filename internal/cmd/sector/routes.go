@@ -0,0 +1,172 @@
+package sector
+
+import (
+	"sort"
+
+	"tas/internal/hexgrid"
+	"tas/internal/model"
+	"tas/internal/util"
+)
+
+/*
+	Routes are not part of the core rules. Xboat routes follow the Imperial practice of linking the important
+	worlds and major starports with the fewest jump-4 (or shorter) links that connect them. Trade routes
+	are rolled for each pair of worlds within jump-4 of each other, with busy starports and large
+	populations making a route more likely and longer jumps less likely
+*/
+
+const (
+	maxRouteParsecs = 4
+
+	xboatHubImportance      = 3 //worlds at least this important are always xboat stops
+	xboatStarportImportance = 1 //A and B starports at least this important are xboat stops
+)
+
+// trade routes are made on 2D + DMs of at least this, by parsecs between the worlds
+var tradeRouteTargetByParsecs = map[int]int{1: 10, 2: 11, 3: 12, 4: 13}
+
+var tradeRouteStarportDM = map[string]int{"A": 2, "B": 1, "C": 0, "D": -1, "E": -2}
+
+type routeWorld struct {
+	hex        hexgrid.Hex
+	location   string
+	starport   string
+	population int
+	importance int
+}
+
+type routePair struct {
+	a, b    *routeWorld
+	parsecs int
+}
+
+// generateRoutes links the worlds of the sector with xboat and trade routes. Each trade route is rolled with its
+// own dice, derived from the sector seed and the two hexes, so routes do not depend on the order worlds were made
+func generateRoutes(ctx *util.TASContext, sector *model.Sector) []*model.Route {
+
+	log := ctx.Logger()
+	log.Info().Msg("Beginning route generation...")
+
+	worlds := make([]*routeWorld, 0, len(sector.Worlds))
+	for _, sw := range sector.Worlds {
+		ws := sw.WorldSummaryData
+		hex, err := ws.Hex()
+		if err != nil {
+			log.Warn().Err(err).Str("world", ws.Name).Msg("world has no usable hex location and is left off all routes")
+			continue
+		}
+		rw := &routeWorld{hex: hex, location: ws.HexLocation, starport: ws.Starport}
+		rw.population, _ = util.EHexAsInt(ws.Population)
+		if ix := ws.ExtendedData.ImportanceDetails; ix != nil {
+			rw.importance = ix.Value
+		}
+		worlds = append(worlds, rw)
+	}
+
+	pairs := routePairs(worlds)
+
+	routes := xboatRoutes(pairs)
+	xboats := len(routes)
+	routes = append(routes, tradeRoutes(ctx.Dice().Seed(), pairs)...)
+
+	log.Info().Int("xboat-routes", xboats).Int("trade-routes", len(routes)-xboats).Msg("Route generation complete")
+	return routes
+}
+
+// routePairs lists every pair of worlds within range of each other, nearest first then by hex so the order is always
+// the same. The world with the lower hex is always first in a pair
+func routePairs(worlds []*routeWorld) []*routePair {
+	pairs := make([]*routePair, 0)
+	for i, a := range worlds {
+		for _, b := range worlds[i+1:] {
+			if d := hexgrid.Distance(a.hex, b.hex); d <= maxRouteParsecs {
+				pa, pb := a, b
+				if pb.location < pa.location {
+					pa, pb = pb, pa
+				}
+				pairs = append(pairs, &routePair{a: pa, b: pb, parsecs: d})
+			}
+		}
+	}
+	sort.SliceStable(pairs, func(i, j int) bool {
+		if pairs[i].parsecs != pairs[j].parsecs {
+			return pairs[i].parsecs < pairs[j].parsecs
+		}
+		if pairs[i].a.location != pairs[j].a.location {
+			return pairs[i].a.location < pairs[j].a.location
+		}
+		return pairs[i].b.location < pairs[j].b.location
+	})
+	return pairs
+}
+
+func isXboatStop(w *routeWorld) bool {
+	if w.importance >= xboatHubImportance {
+		return true
+	}
+	return (w.starport == "A" || w.starport == "B") && w.importance >= xboatStarportImportance
+}
+
+// xboat routes are the shortest set of links that connect every xboat stop that can be reached (a minimum spanning forest)
+func xboatRoutes(pairs []*routePair) []*model.Route {
+
+	//each stop starts in its own network, and networks are merged as links are made
+	network := make(map[*routeWorld]*routeWorld)
+	var find func(w *routeWorld) *routeWorld
+	find = func(w *routeWorld) *routeWorld {
+		if network[w] == w {
+			return w
+		}
+		network[w] = find(network[w])
+		return network[w]
+	}
+
+	routes := make([]*model.Route, 0)
+	for _, p := range pairs {
+		if !isXboatStop(p.a) || !isXboatStop(p.b) {
+			continue
+		}
+		for _, w := range []*routeWorld{p.a, p.b} {
+			if _, ok := network[w]; !ok {
+				network[w] = w
+			}
+		}
+		na, nb := find(p.a), find(p.b)
+		if na == nb {
+			continue
+		}
+		network[na] = nb
+		routes = append(routes, &model.Route{Type: model.RouteTypeXboat, From: p.a.location, To: p.b.location, Parsecs: p.parsecs})
+	}
+	return routes
+}
+
+func tradeRoutes(seed int64, pairs []*routePair) []*model.Route {
+
+	routes := make([]*model.Route, 0)
+	for _, p := range pairs {
+
+		//there is no trade to or from a world without a starport
+		dmA, okA := tradeRouteStarportDM[p.a.starport]
+		dmB, okB := tradeRouteStarportDM[p.b.starport]
+		if !okA || !okB {
+			continue
+		}
+
+		dm := dmA + dmB
+		for _, w := range []*routeWorld{p.a, p.b} {
+			if w.population >= 8 {
+				dm++
+			}
+			if w.population <= 3 {
+				dm--
+			}
+		}
+
+		dice := util.NewDice(util.DeriveSeed(seed, "route", p.a.location, p.b.location))
+		if dice.Sum(2, dm) >= tradeRouteTargetByParsecs[p.parsecs] {
+			routes = append(routes, &model.Route{Type: model.RouteTypeTrade, From: p.a.location, To: p.b.location, Parsecs: p.parsecs})
+		}
+	}
+	return routes
+}
@@ -0,0 +1,30 @@
+package sector
+
+import (
+	"testing"
+
+	"tas/internal/hexgrid"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoutePairsOutOfHexOrder(t *testing.T) {
+
+	//worlds are listed subsector by subsector, so a later world can have a lower hex
+	locations := []string{"0203", "0101", "0204", "0905", "0115"}
+	worlds := make([]*routeWorld, 0, len(locations))
+	for _, l := range locations {
+		hex, err := hexgrid.Parse(l)
+		assert.NoError(t, err)
+		worlds = append(worlds, &routeWorld{hex: hex, location: l})
+	}
+
+	found := make(map[string]int)
+	for _, p := range routePairs(worlds) {
+		assert.Less(t, p.a.location, p.b.location, "the lower hex should come first")
+		assert.Equal(t, hexgrid.Distance(p.a.hex, p.b.hex), p.parsecs, "%s-%s has the wrong distance", p.a.location, p.b.location)
+		found[p.a.location+"-"+p.b.location]++
+	}
+
+	assert.Equal(t, map[string]int{"0101-0203": 1, "0101-0204": 1, "0203-0204": 1}, found)
+}
@@ -2,11 +2,14 @@ package sector
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
+	"sync"
 
 	h "tas/internal/cmd/helpers"
 	"tas/internal/cmd/world"
 
+	"tas/internal/hexgrid"
 	"tas/internal/model"
 	"tas/internal/util"
 
@@ -14,12 +17,18 @@ import (
 )
 
 const (
-	gasGiantThreshold          = 10
+	HexFlagName       = "hex"
+	RerollFlagName    = "reroll"
+	SubsectorFlagName = "subsector"
+	MapFlagName       = "map"
+	FormatFlagName    = "format"
+
+	EmitTradeDataFlagName    = "emit-trade-data"
+	DefaultTradeDataFilename = "trade-data.json"
+
 	shouldCreateWorldThreshold = 4
 )
 
-var sectorMapIDStrings = map[int]string{1: "01", 2: "02", 3: "03", 4: "04", 5: "05", 6: "06", 7: "07", 8: "08", 9: "09", 10: "10"}
-
 var SectorCmdConfig = &cobra.Command{
 
 	Use:   "sector",
@@ -51,7 +60,7 @@ func sectorCmd(cmd *cobra.Command, args []string) {
 	log := util.NewLogger(loglevel)
 	ctx := util.NewContext().
 		WithLogger(log).
-		WithDice().
+		WithDice(cfg.Seed()).
 		WithConfig(cfg)
 
 	//determine if we want standard (as written) worldgen or want to use the custom generator
@@ -63,21 +72,61 @@ func sectorCmd(cmd *cobra.Command, args []string) {
 	}
 	log.Info().Str("scheme", schemeAsString).Msg("scheme used for world generation")
 
+	//determine if a single hex was requested and if that hex should be rerolled
+	onlyHex, _ := cfg.Flags.GetString(HexFlagName)
+	reroll, _ := cfg.Flags.GetInt(RerollFlagName)
+	if onlyHex != "" {
+		if _, _, err := model.ParseHexID(onlyHex); err != nil {
+			log.Error().Err(err).Msg("invalid hex")
+			return
+		}
+	}
+	if reroll < 0 || (reroll > 0 && onlyHex == "") {
+		log.Error().Int("reroll", reroll).Msg("reroll must be a positive number and can only be used with the --hex flag")
+		return
+	}
+
+	//determine if the sector should be exported for other tools
+	format, _ := cfg.Flags.GetString(FormatFlagName)
+	format = strings.ToLower(format)
+	if !validExportFormat(format) {
+		log.Error().Str("format", format).Msg("format must be t5tab or sec")
+		return
+	}
+
+	//determine if a single subsector was requested
+	onlySubsector, _ := cfg.Flags.GetString(SubsectorFlagName)
+	onlySubsector = strings.ToUpper(onlySubsector)
+	if onlySubsector != "" && (len(onlySubsector) != 1 || !strings.Contains(strings.Join(model.SubsectorLetters(), ""), onlySubsector)) {
+		log.Error().Str("subsector", onlySubsector).Msg("subsector must be a single letter from A to P")
+		return
+	}
+
 	//load the data we need to interpret & output a world
 	src, err := world.LoadWorldSourceData(ctx)
 	if err != nil {
 		return
 	}
 
-	//prepare a world namer
+	//prepare world and subsector namers
 	worldNameMgr, err := newWorldNames(ctx)
 	if err != nil {
 		log.Error().Err(err).Msg("unable to prepare world name data")
 		return
 	}
+	subsectorNameMgr, err := newSubsectorNames(ctx, worldNameMgr)
+	if err != nil {
+		log.Error().Err(err).Msg("unable to prepare subsector name data")
+		return
+	}
 
-	//build the subsector
-	sector, err := buildSubSector(ctx, schemeType, src, worldNameMgr)
+	//build the sector
+	rerolls := make(map[string]int)
+	if reroll > 0 {
+		rerolls[onlyHex] = reroll
+	}
+	explain, _ := cfg.Flags.GetBool(world.ExplainFlagName)
+	sector, err := buildSector(ctx, schemeType, src, worldNameMgr, subsectorNameMgr, rerolls, explain)
 	if err != nil {
 		log.Error().Err(err).Msg("Sector creation failed")
 		return
@@ -87,51 +136,134 @@ func sectorCmd(cmd *cobra.Command, args []string) {
 	sectorName := args[0]
 	sector.Name = sectorName
 
-	writeSector(ctx, sector)
+	//link the worlds with xboat and trade routes. This is done for the whole sector so that the routes into a
+	//single hex or subsector match the full sector's
+	sector.Routes = generateRoutes(ctx, sector)
+
+	//limit the output to a single hex or subsector (and the routes that touch it) if requested
+	covered := generatedHexes(onlyHex, onlySubsector)
+	if onlyHex != "" {
+		if _, ok := sector.WorldAt(onlyHex); !ok {
+			log.Error().Str("hex", onlyHex).Int64("seed", sector.Seed).Msg("there is no world at the requested hex")
+			return
+		}
+	}
+	if covered != nil {
+		limitSector(sector, covered)
+	}
+	if onlySubsector != "" {
+		subsectors := make([]*model.Subsector, 0, 1)
+		for _, ss := range sector.Subsectors {
+			if ss.Letter == onlySubsector {
+				subsectors = append(subsectors, ss)
+			}
+		}
+		sector.Subsectors = subsectors
+	}
+
+	writeSector(ctx, sector, covered)
+}
+
+// limitSector keeps only the worlds in the covered hexes and the routes with at least one end in them
+func limitSector(sector *model.Sector, covered func(hex string) bool) {
+	worlds := make([]*model.SectorWorld, 0)
+	for _, sw := range sector.Worlds {
+		if covered(sw.WorldSummaryData.HexLocation) {
+			worlds = append(worlds, sw)
+		}
+	}
+	sector.Worlds = worlds
+
+	routes := make([]*model.Route, 0)
+	for _, r := range sector.Routes {
+		if covered(r.From) || covered(r.To) {
+			routes = append(routes, r)
+		}
+	}
+	sector.Routes = routes
 }
 
-func buildSubSector(ctx *util.TASContext, worldGenScheme h.SchemeType, worldSourceData *model.WorldSource, nameMgr *worldNameMgr) (*model.Sector, error) {
+// Each hex gets its own dice streams derived from the sector seed and the hex location, so the world in one hex
+// never depends on the rolls made for any other hex. This lets a single hex be regenerated (or rerolled) in
+// isolation and lets the hexes be generated in parallel without changing the results. Likewise, presence and
+// names are always worked out for the whole sector, so a subsector generated on its own matches the full sector.
+// The whole sector is always built, as its routes need every world; the caller limits it to a hex or subsector
+func buildSector(ctx *util.TASContext, worldGenScheme h.SchemeType, worldSourceData *model.WorldSource, nameMgr *worldNameMgr, subsectorNameMgr *worldNameMgr, rerolls map[string]int, explain bool) (*model.Sector, error) {
 
 	log := ctx.Logger()
-	dice := ctx.Dice()
+	seed := ctx.Dice().Seed()
 	log.Info().Msg("Beginning sector generation...")
 
 	sector := &model.Sector{
-		Name:   "unknown",
-		Worlds: make([]*model.SectorWorld, 0, 40), //40 is approx number of worlds in a subsector using the standard universe creation algorithm
+		Name:       "unknown",
+		Seed:       seed,
+		Subsectors: make([]*model.Subsector, 0, len(model.SubsectorLetters())),
+		Worlds:     make([]*model.SectorWorld, 0, 640), //640 is approx number of worlds in a sector using the standard universe creation algorithm
 	}
 
-	//i: vertical cols on hex sector map
-	//j: position/'row' in the ith column
-	for col := 1; col <= 8; col++ {
-		for row := 1; row <= 10; row++ {
-
-			//per rule on pg 246
-			if dice.Roll() < shouldCreateWorldThreshold {
-				continue
-			}
+	//subsector names come from their own list, topped up with generated names if it is short
+	subsectorNameMgr.Extend(util.NewDice(util.DeriveSeed(seed, "subsector-names", "generated")), len(model.SubsectorLetters()), nameMgr)
+	subsectorNameMgr.Shuffle(util.NewDice(util.DeriveSeed(seed, "subsector-names")))
+	for i, letter := range model.SubsectorLetters() {
+		sector.Subsectors = append(sector.Subsectors, &model.Subsector{Letter: letter, Name: subsectorNameMgr.Get(i)})
+	}
 
-			def := world.GenerateWorld(ctx, worldGenScheme)
-			worldSummary, err := world.GenerateWorldSummary(ctx, def, worldSourceData)
-			if err != nil {
-				log.Error().Err(err).Msg("unable to generate world")
-				return nil, err
+	//first pass - determine which hexes hold a world, per rule on pg 246. Hexes are visited in
+	//subsector order so that worlds are listed (and named) subsector by subsector
+	//col: vertical cols on hex sector map
+	//row: position/'row' in the col-th column
+	type occupiedHex struct {
+		hex       string
+		subsector string
+	}
+	occupied := make([]occupiedHex, 0, hexgrid.SectorCols*hexgrid.SectorRows)
+	for ssRow := 0; ssRow < hexgrid.SectorRows/model.SubsectorRows; ssRow++ {
+		for ssCol := 0; ssCol < hexgrid.SectorCols/model.SubsectorCols; ssCol++ {
+			for col := ssCol*model.SubsectorCols + 1; col <= (ssCol+1)*model.SubsectorCols; col++ {
+				for row := ssRow*model.SubsectorRows + 1; row <= (ssRow+1)*model.SubsectorRows; row++ {
+					hex := model.HexID(col, row)
+					presenceDice := util.NewDice(util.DeriveSeed(seed, hex, "presence"))
+					if presenceDice.Roll() < shouldCreateWorldThreshold {
+						continue
+					}
+					occupied = append(occupied, occupiedHex{hex: hex, subsector: model.SubsectorForHex(col, row)})
+				}
 			}
+		}
+	}
 
-			//add some data and recalc UWP then do the summary's long desc
-			worldSummary.HexLocation = sectorMapIDStrings[col] + sectorMapIDStrings[row]
-			worldSummary.Name = nameMgr.Get()
-			worldSummary.UWP = worldSummary.ToUWP()
-			world.BuildLongDescription(ctx, worldSummary)
+	//second pass - generate the world in each occupied hex. Each hex has its own dice, so order does not matter
+	worlds := make([]*model.SectorWorld, len(occupied))
+	errs := make([]error, len(occupied))
+	var wg sync.WaitGroup
+	for i, o := range occupied {
+		wg.Add(1)
+		go func(i int, hex string) {
+			defer wg.Done()
+			worlds[i], errs[i] = buildHex(ctx, worldGenScheme, worldSourceData, hex, rerolls[hex], explain)
+		}(i, o.hex)
+	}
+	wg.Wait()
+
+	//names are handed out in hex order from a list shuffled by the sector seed, so a hex keeps its name when rerolled.
+	//A sector has more worlds than the list has names, so the list is topped up with generated names first
+	nameMgr.Extend(util.NewDice(util.DeriveSeed(seed, "world-names", "generated")), len(occupied), subsectorNameMgr)
+	nameMgr.Shuffle(util.NewDice(util.DeriveSeed(seed, "world-names")))
+	for i, sw := range worlds {
+		if errs[i] != nil {
+			log.Error().Err(errs[i]).Msg("unable to generate world")
+			return nil, errs[i]
+		}
 
-			sw := &model.SectorWorld{
-				WorldSummaryData: worldSummary,
-				HasGasGiant:      dice.Sum(2) < gasGiantThreshold,
-			}
+		//add some data and recalc UWP then do the summary's long desc
+		sw.Subsector = occupied[i].subsector
+		worldSummary := sw.WorldSummaryData
+		worldSummary.Name = nameMgr.Get(i)
+		worldSummary.UWP = worldSummary.ToUWP(false)
+		world.BuildLongDescription(ctx, worldSummary)
 
-			sector.Worlds = append(sector.Worlds, sw)
-			log.Info().Str("UWP", worldSummary.UWP).Send()
-		}
+		sector.Worlds = append(sector.Worlds, sw)
+		log.Info().Str("UWP", worldSummary.UWP).Send()
 	}
 
 	log.Info().Int("worlds-generated", len(sector.Worlds)).Msg("Sector generation complere")
@@ -139,22 +271,89 @@ func buildSubSector(ctx *util.TASContext, worldGenScheme h.SchemeType, worldSour
 
 }
 
-func writeSector(ctx *util.TASContext, sector *model.Sector) {
+func buildHex(ctx *util.TASContext, worldGenScheme h.SchemeType, worldSourceData *model.WorldSource, hex string, reroll int, explain bool) (*model.SectorWorld, error) {
+
+	//a reroll picks an alternate dice stream for this hex only
+	hexSeed := util.DeriveSeed(ctx.Dice().Seed(), hex, "world", strconv.Itoa(reroll))
+	hexCtx := ctx.Fork().WithDice(hexSeed)
+	if explain {
+		hexCtx.WithAudit()
+	}
+	def := world.GenerateWorld(hexCtx, worldGenScheme, worldSourceData)
+	worldSummary, err := world.GenerateWorldSummary(hexCtx, def, worldSourceData)
+	if err != nil {
+		return nil, err
+	}
+	worldSummary.HexLocation = hex
+	//the hex's own seed can't be passed back to --seed, so record the sector's seed; with the hex and the reroll it
+	//regenerates this world
+	worldSummary.Seed = ctx.Dice().Seed()
+
+	sw := &model.SectorWorld{
+		WorldSummaryData: worldSummary,
+		Reroll:           reroll,
+	}
+	if explain {
+		worldSummary.Audit = hexCtx.Audit().Entries
+	}
+	return sw, nil
+}
+
+func writeSector(ctx *util.TASContext, sector *model.Sector, covered func(hex string) bool) {
 
 	var sb strings.Builder
 
 	sb.WriteString(h.NL)
 	sb.WriteString(h.NL + fmt.Sprintf("Sector: %s (%d worlds)", sector.Name, len(sector.Worlds)))
+	sb.WriteString(h.NL + fmt.Sprintf("Seed: %d", sector.Seed))
 	sb.WriteString(h.NL + "=====================================")
-	for _, w := range sector.Worlds {
-		sb.WriteString(h.NL + w.WorldSummaryData.ToUWP())
+	withExtensions, _ := ctx.Config().Flags.GetBool(world.ExtensionsFlagName)
+	for _, ss := range sector.Subsectors {
+		worlds := sector.WorldsIn(ss.Letter)
+		if len(worlds) == 0 {
+			continue
+		}
+		sb.WriteString(h.NL)
+		sb.WriteString(h.NL + fmt.Sprintf("Subsector %s: %s (%d worlds)", ss.Letter, ss.Name, len(worlds)))
+		sb.WriteString(h.NL + "-------------------------------------")
+		for _, w := range worlds {
+			sb.WriteString(h.NL + w.WorldSummaryData.ToUWP(withExtensions))
+			sb.WriteString(h.SP + w.WorldSummaryData.PBG + h.SP + w.WorldSummaryData.Stars)
+			sb.WriteString(world.AuditDescription(w.WorldSummaryData.Audit, h.TAB))
+		}
+	}
+
+	if len(sector.Routes) > 0 {
+		sb.WriteString(h.NL)
+		sb.WriteString(h.NL + fmt.Sprintf("Routes (%d)", len(sector.Routes)))
+		sb.WriteString(h.NL + "-------------------------------------")
+		for _, r := range sector.Routes {
+			sb.WriteString(h.NL + fmt.Sprintf("%-5s %s - %s (%d parsecs)", r.Type, r.From, r.To, r.Parsecs))
+		}
 	}
 	fmt.Println(sb.String())
 
+	//draw the map if requested
+	drawMap, _ := ctx.Config().Flags.GetBool(MapFlagName)
+	if drawMap {
+		h.WrappedFileWriter(ctx, RenderSVG(sector), sector.ToFileName()+".svg", sector.ToFileName())
+	}
+
+	//export for other tools if requested
+	format, _ := ctx.Config().Flags.GetString(FormatFlagName)
+	exportSector(ctx, sector, strings.ToLower(format))
+
+	//write the worlds into trade data if requested
+	tradeDataFilename, _ := ctx.Config().Flags.GetString(EmitTradeDataFlagName)
+	if tradeDataFilename != "" {
+		emitTradeData(ctx, sector, tradeDataFilename, covered)
+	}
+
 	//also write to file if requested
 	writeToFile, _ := ctx.Config().Flags.GetBool(util.ToFileFlagName)
 	if writeToFile {
 		sectorName := sector.ToFileName()
+		h.WrappedJSONFileWriter(ctx, sector, sectorName+".json", sectorName)
 		for _, w := range sector.Worlds {
 			h.WrappedJSONFileWriter(ctx, w, w.WorldSummaryData.ToLongFileName(), sectorName)
 		}
@@ -162,48 +361,92 @@ func writeSector(ctx *util.TASContext, sector *model.Sector) {
 }
 
 type worldNameMgr struct {
-	availNames map[int]string
-	dice       util.Dice
+	names []string
 }
 
+const (
+	defaultNamesPath         = "./data-local/"
+	defaultWorldNamesFile    = "world-names.txt"
+	defaultSubsectorNameFile = "subsector-names.txt"
+)
+
 func newWorldNames(ctx *util.TASContext) (*worldNameMgr, error) {
+	return readNames(defaultNamesPath + defaultWorldNamesFile)
+}
 
-	defaultWorldNamesPath := "./data-local/"
-	defaultWorldNamesFile := "world-names.txt"
-	fname := fmt.Sprintf("%s%s", defaultWorldNamesPath, defaultWorldNamesFile)
+// newSubsectorNames reads the subsector names, leaving out any that are also world names
+func newSubsectorNames(ctx *util.TASContext, worldNames *worldNameMgr) (*worldNameMgr, error) {
+	names, err := readNames(defaultNamesPath + defaultSubsectorNameFile)
+	if err != nil {
+		return nil, err
+	}
+	taken := worldNames.taken()
+	kept := make([]string, 0, len(names.names))
+	for _, n := range names.names {
+		if _, dupe := taken[strings.ToLower(n)]; !dupe {
+			kept = append(kept, n)
+		}
+	}
+	names.names = kept
+	return names, nil
+}
+
+func readNames(fname string) (*worldNameMgr, error) {
 
 	rawNames, err := util.ReadWorldNamesFromFile(fname)
 	if err != nil {
 		return nil, err
 	}
-
-	nameMap := make(map[int]string)
-	for i, n := range rawNames {
-		nameMap[i] = n
+	if len(rawNames) == 0 {
+		return nil, fmt.Errorf("no names found in %s", fname)
 	}
 
 	return &worldNameMgr{
-		availNames: nameMap,
-		dice:       ctx.Dice(),
+		names: rawNames,
 	}, nil
 
 }
 
-func (w *worldNameMgr) Get() string {
-
-	cap := len(w.availNames)
-
-	found := false
-	var name string
-	var ok bool
-	for !found {
-		pull := w.dice.Dx(cap)
-		name, ok = w.availNames[pull]
-		if !ok {
+// Extend adds generated names until there are at least n, never repeating a name already held or one held by
+// any of the others
+func (w *worldNameMgr) Extend(dice util.Dice, n int, others ...*worldNameMgr) {
+	taken := w.taken()
+	for _, o := range others {
+		for name := range o.taken() {
+			taken[name] = struct{}{}
+		}
+	}
+	for len(w.names) < n {
+		name := util.GenerateName(dice)
+		if _, dupe := taken[strings.ToLower(name)]; dupe {
 			continue
 		}
-		delete(w.availNames, pull)
-		found = true
+		taken[strings.ToLower(name)] = struct{}{}
+		w.names = append(w.names, name)
+	}
+}
+
+func (w *worldNameMgr) taken() map[string]struct{} {
+	taken := make(map[string]struct{}, len(w.names))
+	for _, name := range w.names {
+		taken[strings.ToLower(name)] = struct{}{}
+	}
+	return taken
+}
+
+// Shuffle randomly orders the available names using the given dice
+func (w *worldNameMgr) Shuffle(dice util.Dice) {
+	for i := len(w.names) - 1; i > 0; i-- {
+		j := dice.Dx(i+1) - 1
+		w.names[i], w.names[j] = w.names[j], w.names[i]
+	}
+}
+
+// Get returns the name for the nth world. Names are never repeated; if the list runs out, names are reused with a numeric suffix
+func (w *worldNameMgr) Get(n int) string {
+	name := w.names[n%len(w.names)]
+	if cycle := n / len(w.names); cycle > 0 {
+		name = fmt.Sprintf("%s %d", name, cycle+1)
 	}
 	return name
 }
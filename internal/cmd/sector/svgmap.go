@@ -0,0 +1,223 @@
+package sector
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"strings"
+
+	"tas/internal/hexgrid"
+	"tas/internal/model"
+	"tas/internal/util"
+)
+
+/*
+	The map is drawn with flat-topped hexes in columns, with even columns shifted down half a hex, as on
+	the published sector maps. Everything is drawn in one self-contained SVG (no external fonts or images)
+	so it can be printed or dropped into a wiki as is
+*/
+
+const (
+	hexRadius   = 40.0 //center to corner
+	mapMargin   = 30.0
+	titleHeight = 40.0
+
+	colorBackground = "#ffffff"
+	colorGrid       = "#9a9a9a"
+	colorSubsector  = "#d0d0d0"
+	colorText       = "#000000"
+	colorHexNumber  = "#6a6a6a"
+	colorWater      = "#2a6fdb"
+	colorDry        = "#b08a4a"
+	colorAmber      = "#f0a000"
+	colorRed        = "#d00000"
+	colorXboat      = "#c03030"
+	colorTrade      = "#3080c0"
+)
+
+// glyphs drawn for each base, in the order they are drawn
+var baseGlyphs = []struct {
+	code  string
+	glyph string
+}{
+	{code: "N", glyph: "★"},
+	{code: "S", glyph: "▲"},
+	{code: "M", glyph: "■"},
+	{code: "C", glyph: "☠"},
+}
+
+type mapBounds struct {
+	minCol, maxCol, minRow, maxRow int
+}
+
+// RenderSVG draws the sector as an SVG hex map. If the sector holds only one subsector, only that subsector is drawn
+func RenderSVG(sector *model.Sector) []byte {
+
+	bounds := mapBounds{minCol: 1, maxCol: hexgrid.SectorCols, minRow: 1, maxRow: hexgrid.SectorRows}
+	title := sector.Name
+	if len(sector.Subsectors) == 1 {
+		ss := sector.Subsectors[0]
+		idx := strings.Index(strings.Join(model.SubsectorLetters(), ""), ss.Letter)
+		bounds.minCol = (idx%(hexgrid.SectorCols/model.SubsectorCols))*model.SubsectorCols + 1
+		bounds.maxCol = bounds.minCol + model.SubsectorCols - 1
+		bounds.minRow = (idx/(hexgrid.SectorCols/model.SubsectorCols))*model.SubsectorRows + 1
+		bounds.maxRow = bounds.minRow + model.SubsectorRows - 1
+		title = fmt.Sprintf("%s - Subsector %s: %s", sector.Name, ss.Letter, ss.Name)
+	}
+
+	hexHeight := math.Sqrt(3) * hexRadius
+	width := mapMargin*2 + hexRadius*2 + float64(bounds.maxCol-bounds.minCol)*1.5*hexRadius
+	height := mapMargin*2 + titleHeight + hexHeight*(float64(bounds.maxRow-bounds.minRow)+1.5)
+
+	//center of the hex at the given column and row
+	center := func(col int, row int) (float64, float64) {
+		x := mapMargin + hexRadius + float64(col-bounds.minCol)*1.5*hexRadius
+		y := mapMargin + titleHeight + hexHeight/2 + float64(row-bounds.minRow)*hexHeight
+		if col%2 == 0 {
+			y += hexHeight / 2
+		}
+		return x, y
+	}
+
+	var sb strings.Builder
+	sb.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	sb.WriteString(fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f" viewBox="0 0 %.0f %.0f" font-family="Helvetica, Arial, sans-serif">`+"\n", width, height, width, height))
+	sb.WriteString(fmt.Sprintf(`<rect width="100%%" height="100%%" fill="%s"/>`+"\n", colorBackground))
+	sb.WriteString(fmt.Sprintf(`<text x="%.1f" y="%.1f" font-size="24" font-weight="bold" fill="%s">%s</text>`+"\n", mapMargin, mapMargin+titleHeight/2, colorText, html.EscapeString(title)))
+
+	//subsector names sit faintly behind the hexes of a full sector map
+	if len(sector.Subsectors) > 1 {
+		sb.WriteString(`<g font-size="48" fill="` + colorSubsector + `" text-anchor="middle">` + "\n")
+		for i, ss := range sector.Subsectors {
+			col := (i%(hexgrid.SectorCols/model.SubsectorCols))*model.SubsectorCols + model.SubsectorCols/2
+			row := (i/(hexgrid.SectorCols/model.SubsectorCols))*model.SubsectorRows + model.SubsectorRows/2
+			x, y := center(col, row)
+			sb.WriteString(fmt.Sprintf(`<text x="%.1f" y="%.1f">%s</text>`+"\n", x+hexRadius*0.75, y, html.EscapeString(ss.Name)))
+		}
+		sb.WriteString("</g>\n")
+	}
+
+	//hex grid and hex numbers
+	sb.WriteString(`<g fill="none" stroke="` + colorGrid + `" stroke-width="1">` + "\n")
+	for col := bounds.minCol; col <= bounds.maxCol; col++ {
+		for row := bounds.minRow; row <= bounds.maxRow; row++ {
+			x, y := center(col, row)
+			sb.WriteString(`<polygon points="` + hexPoints(x, y) + `"/>` + "\n")
+		}
+	}
+	sb.WriteString("</g>\n")
+	sb.WriteString(`<g font-size="9" fill="` + colorHexNumber + `" text-anchor="middle">` + "\n")
+	for col := bounds.minCol; col <= bounds.maxCol; col++ {
+		for row := bounds.minRow; row <= bounds.maxRow; row++ {
+			x, y := center(col, row)
+			sb.WriteString(fmt.Sprintf(`<text x="%.1f" y="%.1f">%s</text>`+"\n", x, y-hexHeight/2+11, model.HexID(col, row)))
+		}
+	}
+	sb.WriteString("</g>\n")
+
+	//routes are drawn under the worlds
+	if len(sector.Routes) > 0 {
+		sb.WriteString(`<g stroke-linecap="round">` + "\n")
+		for _, r := range sector.Routes {
+			fromCol, fromRow, errFrom := model.ParseHexID(r.From)
+			toCol, toRow, errTo := model.ParseHexID(r.To)
+			if errFrom != nil || errTo != nil {
+				continue
+			}
+			x1, y1 := center(fromCol, fromRow)
+			x2, y2 := center(toCol, toRow)
+			style := `stroke="` + colorTrade + `" stroke-width="2" stroke-dasharray="6,4"`
+			if r.Type == model.RouteTypeXboat {
+				style = `stroke="` + colorXboat + `" stroke-width="4" stroke-opacity="0.6"`
+			}
+			sb.WriteString(fmt.Sprintf(`<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" %s/>`+"\n", x1, y1, x2, y2, style))
+		}
+		sb.WriteString("</g>\n")
+	}
+
+	//worlds
+	for _, sw := range sector.Worlds {
+		col, row, err := model.ParseHexID(sw.WorldSummaryData.HexLocation)
+		if err != nil || col < bounds.minCol || col > bounds.maxCol || row < bounds.minRow || row > bounds.maxRow {
+			continue
+		}
+		x, y := center(col, row)
+		sb.WriteString(worldSVG(sw, x, y))
+	}
+
+	sb.WriteString("</svg>\n")
+	return []byte(sb.String())
+}
+
+func worldSVG(sw *model.SectorWorld, x float64, y float64) string {
+	w := sw.WorldSummaryData
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf(`<g text-anchor="middle" fill="%s">`+"\n", colorText))
+
+	//starport class above the world
+	sb.WriteString(fmt.Sprintf(`<text x="%.1f" y="%.1f" font-size="12" font-weight="bold">%s</text>`+"\n", x, y-9, w.Starport))
+
+	//zone ring around the world
+	switch w.TravelZone {
+	case "A":
+		sb.WriteString(fmt.Sprintf(`<circle cx="%.1f" cy="%.1f" r="14" fill="none" stroke="%s" stroke-width="2"/>`+"\n", x, y+3, colorAmber))
+	case "R":
+		sb.WriteString(fmt.Sprintf(`<circle cx="%.1f" cy="%.1f" r="14" fill="none" stroke="%s" stroke-width="2"/>`+"\n", x, y+3, colorRed))
+	}
+
+	//the world itself - blue if it has water, otherwise a dry world
+	worldColor := colorDry
+	if hydro, err := util.EHexAsInt(w.Hydrographics); err == nil && hydro > 0 {
+		worldColor = colorWater
+	}
+	sb.WriteString(fmt.Sprintf(`<circle cx="%.1f" cy="%.1f" r="5" fill="%s"/>`+"\n", x, y+3, worldColor))
+
+	//gas giant marker to the right of the world
+	if sw.HasGasGiant() {
+		sb.WriteString(fmt.Sprintf(`<circle cx="%.1f" cy="%.1f" r="3" fill="%s"/>`+"\n", x+18, y-12, colorText))
+	}
+
+	//base glyphs down the left of the world
+	glyphY := y - 10
+	for _, bg := range baseGlyphs {
+		for _, b := range w.Bases {
+			if b == bg.code {
+				sb.WriteString(fmt.Sprintf(`<text x="%.1f" y="%.1f" font-size="9">%s</text>`+"\n", x-18, glyphY, bg.glyph))
+				glyphY += 10
+			}
+		}
+	}
+
+	//name is larger (and capitalized) for more populous worlds
+	pop, _ := util.EHexAsInt(w.Population)
+	name := w.Name
+	fontSize := 9
+	fontWeight := "normal"
+	switch {
+	case pop >= 9:
+		name = strings.ToUpper(name)
+		fontSize = 11
+		fontWeight = "bold"
+	case pop >= 4:
+		fontSize = 10
+	}
+	sb.WriteString(fmt.Sprintf(`<text x="%.1f" y="%.1f" font-size="%d" font-weight="%s">%s</text>`+"\n", x, y+21, fontSize, fontWeight, html.EscapeString(name)))
+
+	//core UWP under the name
+	uwp := w.Starport + w.Size + w.Atmosphere + w.Hydrographics + w.Population + w.Government + w.LawLevel + "-" + w.TechLevel
+	sb.WriteString(fmt.Sprintf(`<text x="%.1f" y="%.1f" font-size="8">%s</text>`+"\n", x, y+30, uwp))
+
+	sb.WriteString("</g>\n")
+	return sb.String()
+}
+
+// corners of a flat-topped hex around the given center
+func hexPoints(x float64, y float64) string {
+	points := make([]string, 0, 6)
+	for i := 0; i < 6; i++ {
+		angle := math.Pi / 3 * float64(i)
+		points = append(points, fmt.Sprintf("%.1f,%.1f", x+hexRadius*math.Cos(angle), y+hexRadius*math.Sin(angle)))
+	}
+	return strings.Join(points, " ")
+}
@@ -0,0 +1,146 @@
+package sector
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	h "tas/internal/cmd/helpers"
+	"tas/internal/model"
+	"tas/internal/util"
+)
+
+/*
+	The trade commands read their worlds from a trade data file in data-local (see the trade command). Rather
+	than typing that file up from sector output, it can be written from the sector itself. An existing file
+	keeps its character data, the worlds of any other sector and, if only a hex or subsector was generated, the
+	sector's worlds outside it; the old file is kept with a .bak extension
+*/
+
+const (
+	defaultTradeDataPath = "./data-local/"
+	tradeDataBackupExt   = ".bak"
+)
+
+// emitTradeData writes the sector's worlds into the named trade data file in data-local. covered is as for
+// generatedHexes
+func emitTradeData(ctx *util.TASContext, sector *model.Sector, filename string, covered func(hex string) bool) {
+
+	log := ctx.Logger()
+	path := filepath.Join(defaultTradeDataPath, filename)
+
+	facts := &model.TradeFacts{CharacterData: &model.CharacterDataType{}}
+	existing, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		facts, err = model.TradeFactsFromFile(existing)
+		if err != nil {
+			log.Error().Err(err).Str("file", path).Msg("unable to read the existing trade data, so it has not been changed")
+			return
+		}
+		if facts.CharacterData == nil {
+			facts.CharacterData = &model.CharacterDataType{}
+		}
+	case !errors.Is(err, fs.ErrNotExist):
+		log.Error().Err(err).Str("file", path).Msg("unable to read the existing trade data, so it has not been changed")
+		return
+	}
+
+	//the new sector goes at 0,0 so it should be placed by hand if the trade data holds other sectors
+	listed := false
+	for _, s := range facts.Sectors {
+		listed = listed || s.Name == sector.Name
+	}
+	if len(facts.Sectors) > 0 && !listed {
+		log.Warn().Str("sector", sector.Name).Msg("the sector has been placed at 0,0 in the trade data - set its x and y so distances to other sectors are right")
+	}
+	facts.ReplaceSectorWorlds(sector.Name, sectorTradeWorlds(ctx, sector, facts, covered), covered)
+
+	if errs := facts.Validate(); len(errs) > 0 {
+		for _, e := range errs {
+			log.Error().Err(e).Send()
+		}
+		log.Error().Str("file", path).Msg("the trade data would not be valid, so it has not been changed")
+		return
+	}
+
+	b, err := json.MarshalIndent(facts, "", "  ")
+	if err != nil {
+		log.Error().Err(err).Msg("unable to marshal trade data to JSON")
+		return
+	}
+	if existing != nil {
+		if err := os.WriteFile(path+tradeDataBackupExt, existing, 0644); err != nil {
+			log.Error().Err(err).Str("file", path+tradeDataBackupExt).Msg("unable to back up the existing trade data, so it has not been changed")
+			return
+		}
+	}
+	if err := os.WriteFile(path, append(b, h.NL...), 0644); err != nil {
+		log.Error().Err(err).Str("file", path).Msg("unable to write trade data")
+		return
+	}
+	log.Info().Str("file", path).Int("worlds", len(facts.RawWorldTradeInfo)).Msg("trade data written")
+}
+
+// generatedHexes is true for the hexes generated when only a hex (--hex) or subsector (--subsector) was asked for,
+// so only the trade data for those hexes is replaced. It is nil when the whole sector was generated
+func generatedHexes(onlyHex string, onlySubsector string) func(hex string) bool {
+	switch {
+	case onlyHex != "":
+		return func(hex string) bool {
+			return hex == onlyHex
+		}
+	case onlySubsector != "":
+		return func(hex string) bool {
+			col, row, err := model.ParseHexID(hex)
+			return err == nil && model.SubsectorForHex(col, row) == onlySubsector
+		}
+	}
+	return nil
+}
+
+// sectorTradeWorlds lists the sector's worlds as the trade commands expect them, e.g. 'C69A988-8 S Hi In Wa A'.
+// World names must be unique in the trade data, so a repeated name has its hex added
+func sectorTradeWorlds(ctx *util.TASContext, sector *model.Sector, facts *model.TradeFacts, covered func(hex string) bool) []*model.WorldTradeInfoType {
+
+	log := ctx.Logger()
+
+	//the names of the worlds that are kept
+	names := make(map[string]struct{})
+	for _, w := range facts.RawWorldTradeInfo {
+		if w.Sector != sector.Name || (covered != nil && !covered(w.Hex)) {
+			names[w.Name] = struct{}{}
+		}
+	}
+
+	worlds := make([]*model.WorldTradeInfoType, 0, len(sector.Worlds))
+	for _, sw := range sector.Worlds {
+		ws := sw.WorldSummaryData
+
+		parts := []string{coreUWP(ws)}
+		if len(ws.Bases) > 0 {
+			parts = append(parts, ws.Bases...)
+		}
+		parts = append(parts, exportTradeCodes(ws)...)
+		if zone := exportZone(ws); zone != "" {
+			parts = append(parts, zone)
+		}
+		uwp := strings.Join(parts, h.SP)
+		if !model.ValidTradeUWP(uwp) {
+			log.Warn().Str("world", ws.Name).Str("uwp", uwp).Msg("the world's UWP is outside the ranges used by the trade rules, so it is left out of the trade data")
+			continue
+		}
+
+		name := ws.Name
+		if _, dupe := names[name]; dupe {
+			name += " (" + ws.HexLocation + ")"
+		}
+		names[name] = struct{}{}
+
+		worlds = append(worlds, &model.WorldTradeInfoType{Name: name, UWP: uwp, Hex: ws.HexLocation, Sector: sector.Name})
+	}
+	return worlds
+}
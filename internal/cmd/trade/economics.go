@@ -0,0 +1,295 @@
+package trade
+
+import (
+	"fmt"
+	"strings"
+
+	h "tas/internal/cmd/helpers"
+	"tas/internal/cmd/world"
+	"tas/internal/model"
+	"tas/internal/util"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	FuelFlagName  = "fuel"
+	FuelRefined   = "refined"
+	FuelUnrefined = "unrefined"
+
+	//life support costs per month
+	lifeSupportPerStateroom = 1000
+	lifeSupportPerLowBerth  = 100
+
+	//costs per ton of fuel
+	refinedFuelPerTon   = 500
+	unrefinedFuelPerTon = 100
+
+	//a jump takes a week, and about another week is spent in port and moving in-system
+	jumpsPerMonth = 2
+
+	mailLotPayment  = 25000
+	mailCheckTarget = 12
+)
+
+var EconomicsCmdConfig = &cobra.Command{
+
+	Use:   "economics",
+	Short: "sets the running costs of the ship against the income from a planned trip",
+	Run:   economicsCmd,
+
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 && len(args) != 2 {
+			return fmt.Errorf("1 or 2 arguments required - the source (the ledger's current world if not given) and destination world names")
+		}
+		return nil
+	},
+}
+
+func economicsCmd(cmd *cobra.Command, args []string) {
+
+	//create a config to hold all data passed into this call
+	cfg, err := util.NewTASConfig().
+		WithArgs(args).
+		WithCmd(cmd)
+	if err != nil {
+		fmt.Println()
+		fmt.Printf("Unable to create config. This is a critical error: %s\n", err)
+		fmt.Println()
+		return
+	}
+
+	//build a context to make all data easily available between calls
+	loglevel, _ := cfg.Flags.GetString(util.LogLevelFlagName)
+	log := util.NewLogger(loglevel)
+	ctx := util.NewContext().
+		WithLogger(log).
+		WithDice(cfg.Seed()).
+		WithConfig(cfg)
+
+	fuelType, _ := cfg.Flags.GetString(FuelFlagName)
+	if fuelType != FuelRefined && fuelType != FuelUnrefined {
+		log.Error().Str("fuel", fuelType).Msgf("fuel must be %s or %s", FuelRefined, FuelUnrefined)
+		return
+	}
+
+	//load the data we need: trade data, the ship and the starport costs
+	tradeFacts, err := LoadStandardTradeFacts(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("unable to open trade facts file")
+		return
+	}
+	if !tradeFacts.ShipProfile().IsFullProfile() {
+		log.Error().Msgf("the economics of a trip need a ship profile with the ship's tonnage and costs (see --%s)", ShipFileFlagName)
+		return
+	}
+	starports, err := world.LoadStarports(ctx)
+	if err != nil {
+		return
+	}
+
+	from, to, err := tripWorlds(ctx, args)
+	if err != nil {
+		log.Error().Err(err).Msg("unable to find the source world")
+		return
+	}
+
+	trade, err := GenerateStandardTrade(ctx, from, to, tradeFacts)
+	if err != nil {
+		log.Error().Err(err).Msg("trade DM calculations failed")
+		return
+	}
+
+	economics, err := GenerateTripEconomics(ctx, trade, tradeFacts, starports, fuelType)
+	if err != nil {
+		log.Error().Err(err).Msg("trip economics calculations failed")
+		return
+	}
+
+	writeEconomicsOutput(ctx, economics)
+}
+
+// GenerateTripEconomics resolves the passengers, mail and freight for the trip and sets the income from them against
+// the ship's costs. The monthly costs are shared out by the number of jumps (two jumps a month); fuel and berthing
+// at the destination are paid for the trip. If no check was given for passengers or freight, an Effect of 0 is used
+func GenerateTripEconomics(ctx *util.TASContext, trade *model.StandardTradeModifiers, tradeFacts *model.TradeFacts, starports model.WorldStarportMap, fuelType string) (*model.TripEconomics, error) {
+
+	log := ctx.Logger()
+	log.Info().Msg("Beginning trip economics...")
+
+	ship := tradeFacts.ShipProfile()
+	fromData, _ := tradeFacts.DataForWorldName(trade.From)
+	toData, _ := tradeFacts.DataForWorldName(trade.To)
+
+	economics := &model.TripEconomics{
+		From:          trade.From,
+		To:            trade.To,
+		Ship:          ship.Name,
+		Seed:          trade.Seed,
+		Parsecs:       h.MaxInt(trade.Parsecs, 1),
+		Jumps:         h.MaxInt(trade.JumpsRequired, 1),
+		EconomicNotes: make([]string, 0),
+	}
+	if trade.Parsecs == 0 {
+		economics.EconomicNotes = append(economics.EconomicNotes, "The distance is unknown, so a single jump of one parsec is assumed.")
+	}
+
+	//passengers
+	effect, given, err := trafficCheck(ctx, EffectFlagName, SkillFlagName)
+	if err != nil {
+		return nil, err
+	}
+	if !given {
+		economics.EconomicNotes = append(economics.EconomicNotes, fmt.Sprintf("No check was given to find passengers (see --%s), so an Effect of 0 was used.", EffectFlagName))
+	}
+	ResolvePassengers(ctx, trade, effect, tradeFacts.JumpRating(), ship)
+
+	//mail and freight share the cargo space free for this trip
+	cargoTons, cargoKnown, err := cargoSpace(ctx, ship)
+	if err != nil {
+		return nil, err
+	}
+
+	//mail is only carried if the check succeeds and every lot fits in the hold
+	mailDice := util.NewDice(util.DeriveSeed(ctx.Dice().Seed(), "mail-check"))
+	mailRoll := mailDice.Sum(2) + trade.MailTrade.MailDM
+	mailTons := trade.MailTrade.LotsAvail * mailLotTons
+	mailIncome := 0
+	switch {
+	case mailRoll < mailCheckTarget:
+		economics.EconomicNotes = append(economics.EconomicNotes, fmt.Sprintf("The mail check was failed (rolled %d, needs %d+), so no mail is carried.", mailRoll, mailCheckTarget))
+		mailTons = 0
+	case !cargoKnown:
+		economics.EconomicNotes = append(economics.EconomicNotes, fmt.Sprintf("The mail check succeeded but the ship's cargo space is not known (see --%s), so no mail is carried.", CargoFlagName))
+		mailTons = 0
+	case mailTons > cargoTons:
+		economics.EconomicNotes = append(economics.EconomicNotes, fmt.Sprintf("The mail check succeeded but the hold cannot take all %dT of mail, so no mail is carried.", mailTons))
+		mailTons = 0
+	default:
+		mailIncome = trade.MailTrade.LotsAvail * mailLotPayment
+	}
+
+	//freight fills what is left of the hold after the mail
+	effect, given, err = trafficCheck(ctx, FreightEffectFlagName, FreightSkillFlagName)
+	if err != nil {
+		return nil, err
+	}
+	if !given {
+		economics.EconomicNotes = append(economics.EconomicNotes, fmt.Sprintf("No check was given to find freight (see --%s), so an Effect of 0 was used.", FreightEffectFlagName))
+	}
+	ResolveFreight(ctx, trade, effect, tradeFacts.JumpRating(), h.MaxInt(cargoTons-mailTons, 0), cargoKnown)
+
+	economics.Income = []*model.TripLineItem{
+		{Item: "Passengers", Credits: trade.PassengerTrade.TotalIncome},
+		{Item: "Freight", Credits: trade.FreightTrade.TotalIncome, Note: fmt.Sprintf("%dT", trade.FreightTrade.TonsCarried)},
+		{Item: "Mail", Credits: mailIncome, Note: fmt.Sprintf("%dT", mailTons)},
+	}
+
+	//monthly costs, with life support for the crew and the passengers carried
+	carried := make(map[string]int)
+	for _, t := range trade.PassengerTrade.Traffic {
+		carried[t.PassageType] = t.Carried
+	}
+	staterooms := len(ship.Crew) + carried["high"] + carried["middle"] + (carried["basic"]+1)/2
+	salaries := 0
+	for _, c := range ship.Crew {
+		salaries += c.Salary
+	}
+	economics.MonthlyCosts = []*model.TripLineItem{
+		{Item: "Mortgage", Credits: ship.OperatingCosts.Mortgage},
+		{Item: "Maintenance", Credits: ship.OperatingCosts.Maintenance},
+		{Item: "Crew salaries", Credits: salaries, Note: fmt.Sprintf("%d crew", len(ship.Crew))},
+		{Item: "Life support", Credits: staterooms*lifeSupportPerStateroom + carried["low"]*lifeSupportPerLowBerth, Note: fmt.Sprintf("%d staterooms and %d low berths occupied", staterooms, carried["low"])},
+	}
+	monthly := 0
+	for _, c := range economics.MonthlyCosts {
+		monthly += c.Credits
+	}
+
+	//trip costs: a share of the monthly costs, the fuel and berthing at the destination
+	fuelTons := economics.Parsecs * ship.JumpFuelPerParsec()
+	fuelPrice := refinedFuelPerTon
+	if fuelType == FuelUnrefined {
+		fuelPrice = unrefinedFuelPerTon
+	}
+	economics.TripCosts = []*model.TripLineItem{
+		{Item: "Share of monthly costs", Credits: (monthly*economics.Jumps + jumpsPerMonth - 1) / jumpsPerMonth, Note: fmt.Sprintf("%d jumps at %d jumps a month", economics.Jumps, jumpsPerMonth)},
+		{Item: "Fuel", Credits: fuelTons * fuelPrice, Note: fmt.Sprintf("%dT of %s fuel at Cr%d per ton", fuelTons, fuelType, fuelPrice)},
+	}
+	if fromPort, ok := starports.ByCode(fromData.Starport); ok {
+		if (fuelType == FuelRefined && !fromPort.HasRefinedFuel()) || (fuelType == FuelUnrefined && !fromPort.HasUnrefinedFuel()) {
+			economics.EconomicNotes = append(economics.EconomicNotes, fmt.Sprintf("The starport at %s (%s) does not sell %s fuel.", trade.From, fromPort.Code, fuelType))
+		}
+	}
+	if toPort, ok := starports.ByCode(toData.Starport); ok {
+		economics.TripCosts = append(economics.TripCosts, &model.TripLineItem{
+			Item:    "Berthing",
+			Credits: toPort.BerthingCost * 7 / 2, //the average of 1D is 3.5
+			Note:    fmt.Sprintf("starport %s at %s, 1D x Cr%d (Cr%d - Cr%d), the average is used", toPort.Code, trade.To, toPort.BerthingCost, toPort.BerthingCost, toPort.BerthingCost*6),
+		})
+	}
+	if economics.Jumps > 1 {
+		economics.EconomicNotes = append(economics.EconomicNotes, "Berthing is only included for the destination, not for any stops along the way.")
+	}
+
+	for _, c := range economics.TripCosts {
+		economics.TotalCost += c.Credits
+	}
+	for _, i := range economics.Income {
+		economics.TotalIncome += i.Credits
+	}
+	economics.Profit = economics.TotalIncome - economics.TotalCost
+	economics.CoversCosts = economics.Profit >= 0
+
+	log.Info().Int("profit", economics.Profit).Msg("Trip economics complete")
+	return economics, nil
+}
+
+func writeEconomicsOutput(ctx *util.TASContext, economics *model.TripEconomics) {
+	var sb strings.Builder
+
+	writeItems := func(title string, items []*model.TripLineItem, total int) {
+		sb.WriteString(h.NL)
+		sb.WriteString(h.NL + title)
+		for _, i := range items {
+			line := fmt.Sprintf("%-24s Cr%d", i.Item+":", i.Credits)
+			if i.Note != "" {
+				line += " (" + i.Note + ")"
+			}
+			sb.WriteString(h.NL + h.TAB + line)
+		}
+		sb.WriteString(h.NL + h.TAB + fmt.Sprintf("%-24s Cr%d", "Total:", total))
+	}
+
+	sb.WriteString(fmt.Sprintf("Trip Economics: %s to %s", economics.From, economics.To))
+	sb.WriteString(h.NL + "Ship: " + economics.Ship)
+	sb.WriteString(h.NL + fmt.Sprintf("Distance: %d parsecs (%d jumps)", economics.Parsecs, economics.Jumps))
+
+	monthly := 0
+	for _, c := range economics.MonthlyCosts {
+		monthly += c.Credits
+	}
+	writeItems("Monthly Costs", economics.MonthlyCosts, monthly)
+	writeItems("Trip Costs", economics.TripCosts, economics.TotalCost)
+	writeItems("Trip Income", economics.Income, economics.TotalIncome)
+
+	sb.WriteString(h.NL)
+	if economics.CoversCosts {
+		sb.WriteString(h.NL + fmt.Sprintf("The trip covers its costs, with a profit of Cr%d.", economics.Profit))
+	} else {
+		sb.WriteString(h.NL + fmt.Sprintf("The trip does not cover its costs, falling short by Cr%d.", -economics.Profit))
+	}
+
+	sb.WriteString(h.NL)
+	for _, n := range economics.EconomicNotes {
+		sb.WriteString(h.NL + h.TAB + n)
+	}
+
+	fmt.Println(sb.String())
+
+	//also write to file if requested
+	writeToFile, _ := ctx.Config().Flags.GetBool(util.ToFileFlagName)
+	if writeToFile {
+		h.WrappedJSONFileWriter(ctx, economics, economics.ToFileName())
+	}
+}
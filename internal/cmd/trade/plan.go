@@ -0,0 +1,433 @@
+package trade
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	h "tas/internal/cmd/helpers"
+	"tas/internal/hexgrid"
+	"tas/internal/model"
+	"tas/internal/util"
+
+	"github.com/spf13/cobra"
+)
+
+/*
+	The trade plan looks at where the goods for sale at the current world are best taken. Every lot is paired with
+	every world within one jump, and the purchase and sale price rolls (3D + Broker + the lot's DM on the Modified
+	Price table, pg 243) are simulated many times to give the expected profit per ton and how much it varies. The
+	best cargo for each destination is then picked, lot by lot, from the most profitable goods that fit the hold
+	and the budget
+*/
+
+const (
+	TrialsFlagName = "trials"
+	BudgetFlagName = "budget"
+
+	DefaultTrials = 1000
+
+	pairsShown = 10
+)
+
+var PlanCmdConfig = &cobra.Command{
+
+	Use:   "plan",
+	Short: "simulates buying the goods at the current world and selling them at each world within jump range to find the most profitable",
+	Run:   planCmd,
+
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) > 1 {
+			return fmt.Errorf("at most 1 argument - the current world name (the ledger's current world if not given)")
+		}
+		return nil
+	},
+}
+
+func planCmd(cmd *cobra.Command, args []string) {
+
+	//create a config to hold all data passed into this call
+	cfg, err := util.NewTASConfig().
+		WithArgs(args).
+		WithCmd(cmd)
+	if err != nil {
+		fmt.Println()
+		fmt.Printf("Unable to create config. This is a critical error: %s\n", err)
+		fmt.Println()
+		return
+	}
+
+	//build a context to make all data easily available between calls
+	loglevel, _ := cfg.Flags.GetString(util.LogLevelFlagName)
+	log := util.NewLogger(loglevel)
+	ctx := util.NewContext().
+		WithLogger(log).
+		WithDice(cfg.Seed()).
+		WithConfig(cfg)
+
+	//load the data we need to build speculative trade data
+	tradeFacts, tradeGoodsMap, err := LoadSpeculativeTradeFacts(ctx)
+	if err != nil {
+		return
+	}
+	tradeFacts.Ship, err = LoadShipProfile(ctx)
+	if err != nil {
+		return
+	}
+	ship := tradeFacts.ShipProfile()
+
+	//the current world, and the budget from the ledger if the current world is the ledger's. With neither a ledger
+	//nor a budget given, the budget is not limited
+	localWorldName := ""
+	budget, budgetLimited := 0, false
+	if len(args) == 1 {
+		localWorldName = args[0]
+	} else {
+		l, err := h.LoadLedger(ctx)
+		if err != nil {
+			log.Error().Err(err).Msg("unable to find the current world")
+			return
+		}
+		localWorldName = l.World
+		budget, budgetLimited = h.MaxInt(l.Credits, 0), true
+		if budget == 0 {
+			log.Warn().Int("credits", l.Credits).Msg("the ledger has no credits to spend, so no cargo can be bought")
+		}
+	}
+	if cfg.Flags.Changed(BudgetFlagName) {
+		budget, _ = cfg.Flags.GetInt(BudgetFlagName)
+		budgetLimited = true
+		if budget < 0 {
+			log.Error().Int("budget", budget).Msg("the budget can't be negative")
+			return
+		}
+	}
+	cargoTons, cargoLimited, err := cargoSpace(ctx, ship)
+	if err != nil {
+		log.Error().Err(err).Msg("unable to plan trade")
+		return
+	}
+
+	localData, ok := tradeFacts.DataForWorldName(localWorldName)
+	if !ok {
+		err := fmt.Errorf("the local world: %s is not defined in the trade data file", localWorldName)
+		log.Error().Err(err).Msg("unable to plan trade")
+		return
+	}
+
+	//without a Broker skill given, the crew's best is used for a ship profile; character data holds no Broker skill
+	brokerSkill, hiredBroker, given, err := priceBroker(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("unable to plan trade")
+		return
+	}
+	brokerNote := ""
+	switch {
+	case given:
+	case ship.IsFullProfile():
+		brokerSkill = ship.SkillCheckDM("broker")
+		brokerNote = fmt.Sprintf("No Broker skill was given, so the crew's best (DM %+d) was used.", brokerSkill)
+	default:
+		brokerNote = "No Broker skill was given and the character data does not hold one, so DM+0 was used."
+	}
+	feePercent, _ := cfg.Flags.GetInt(BrokerFeeFlagName)
+	trials, _ := cfg.Flags.GetInt(TrialsFlagName)
+	if trials < 1 {
+		log.Error().Int("trials", trials).Msg("at least 1 trial is needed")
+		return
+	}
+
+	//the goods for sale are found just as for 'trade spec buy'
+	summary := GenerateSpeculativeTrade(ctx, localData, tradeGoodsMap, true)
+
+	plan := &model.TradePlan{
+		World:         localWorldName,
+		Seed:          ctx.Dice().Seed(),
+		Trials:        trials,
+		JumpRange:     tradeFacts.JumpRating(),
+		BrokerSkill:   brokerSkill,
+		HiredBroker:   hiredBroker,
+		CargoTons:     cargoTons,
+		CargoLimited:  cargoLimited,
+		Budget:        budget,
+		BudgetLimited: budgetLimited,
+		Notes:         make([]string, 0),
+	}
+	if hiredBroker {
+		plan.BrokerFeePercent = feePercent
+	}
+	if brokerNote != "" {
+		plan.Notes = append(plan.Notes, brokerNote)
+	}
+
+	if err := GenerateTradePlan(ctx, plan, localData, summary.TradeLots, tradeFacts, tradeGoodsMap); err != nil {
+		log.Error().Err(err).Msg("unable to plan trade")
+		return
+	}
+	writePlanOutput(ctx, plan)
+}
+
+// GenerateTradePlan simulates the price rolls for every lot at every world within jump range of the current world,
+// then picks the best cargo for each destination
+func GenerateTradePlan(ctx *util.TASContext, plan *model.TradePlan, localData *model.WorldTradeInfo, lots []*model.SpeculativeTradeLot, tradeFacts *model.TradeFacts, tradeGoodsMap model.TradeGoodsMap) error {
+	log := ctx.Logger()
+	log.Info().Msg("Beginning trade plan generation...")
+
+	if localData.Hex == nil {
+		return fmt.Errorf("the world: %s has no hex location, so the worlds within jump range are not known", plan.World)
+	}
+	if plan.JumpRange < 1 {
+		return model.ErrCannotJump
+	}
+
+	dice := util.NewDice(util.DeriveSeed(ctx.Dice().Seed(), "trade-plan"))
+
+	brokerDM := plan.BrokerSkill
+	if plan.HiredBroker {
+		brokerDM += hiredBrokerDM
+	}
+
+	//destinations are taken in the order of the trade data file so the dice always fall the same way
+	for _, raw := range tradeFacts.RawWorldTradeInfo {
+		if raw.Name == plan.World {
+			continue
+		}
+		destData, _ := tradeFacts.DataForWorldName(raw.Name)
+		if destData.Hex == nil {
+			log.Debug().Str("world", raw.Name).Msg("world has no hex location and is left out of the plan")
+			continue
+		}
+		parsecs := hexgrid.Distance(*localData.Hex, *destData.Hex)
+		if parsecs > plan.JumpRange {
+			continue
+		}
+
+		for _, l := range lots {
+			good, ok := tradeGoodByType(tradeGoodsMap, l.Type)
+			if !ok {
+				continue
+			}
+			pair := &model.TradePlanPair{
+				LotId:       l.LotId,
+				Goods:       l.Type,
+				Destination: raw.Name,
+				Parsecs:     parsecs,
+				TonsAvail:   l.TonsAvail,
+				BasePrice:   l.BasePrice,
+				PurchaseDM:  l.OfferPriceDM,
+				SaleDM:      calculatePriceDM(destData, good, false),
+			}
+			simulatePair(dice, pair, plan.Trials, brokerDM, plan.BrokerFeePercent)
+			plan.Pairs = append(plan.Pairs, pair)
+		}
+	}
+	if len(plan.Pairs) == 0 {
+		return fmt.Errorf("there are no worlds with a hex location within jump-%d of %s", plan.JumpRange, plan.World)
+	}
+
+	sort.SliceStable(plan.Pairs, func(i, j int) bool {
+		return plan.Pairs[i].ExpectedProfit > plan.Pairs[j].ExpectedProfit
+	})
+	plan.Loads = planLoads(plan)
+
+	plan.Notes = append(plan.Notes,
+		fmt.Sprintf("Each pair was simulated %d times, rolling 3D + Broker %d + the purchase or sale DM on the Modified Price table (pg 243).", plan.Trials, brokerDM),
+		"The goods are those for sale in this roll of 'trade spec buy' with the same seed; the sale DMs assume the buyer's own broker, as for 'trade spec sell'.")
+	if plan.HiredBroker {
+		plan.Notes = append(plan.Notes, fmt.Sprintf("The hired broker's fee of %d%% of the purchase and sale prices is taken from the profit.", plan.BrokerFeePercent))
+	}
+
+	log.Info().Msg("Trade plan generation complete")
+	return nil
+}
+
+// simulatePair rolls the purchase and sale prices of the pair's goods many times, and works out the mean prices and
+// the mean and variance of the profit per ton. A hired broker's fee is taken from both prices
+func simulatePair(dice util.Dice, pair *model.TradePlanPair, trials int, brokerDM int, feePercent int) {
+	var purchaseSum, saleSum, profitSum, profitSquares float64
+	losses := 0
+
+	for t := 0; t < trials; t++ {
+		purchase := float64(pair.BasePrice*modifiedPricePercent(dice.Sum(3)+brokerDM+pair.PurchaseDM, true)) / 100
+		sale := float64(pair.BasePrice*modifiedPricePercent(dice.Sum(3)+brokerDM+pair.SaleDM, false)) / 100
+		profit := sale - purchase - (purchase+sale)*float64(feePercent)/100
+
+		purchaseSum += purchase
+		saleSum += sale
+		profitSum += profit
+		profitSquares += profit * profit
+		if profit < 0 {
+			losses++
+		}
+	}
+
+	n := float64(trials)
+	mean := profitSum / n
+	variance := math.Max(profitSquares/n-mean*mean, 0)
+
+	pair.MeanPurchase = int(math.Round(purchaseSum / n))
+	pair.MeanSale = int(math.Round(saleSum / n))
+	pair.ExpectedProfit = int(math.Round(mean))
+	pair.ProfitVariance = int(math.Round(variance))
+	pair.ProfitStdDev = int(math.Round(math.Sqrt(variance)))
+	pair.LossPercent = losses * 100 / trials
+}
+
+// planLoads picks the cargo for each destination from the goods expected to make a profit there, taking as many
+// tons of each as the hold and the budget allow. The goods are taken both in order of profit per ton (best when the
+// hold is what runs out) and in order of profit per credit spent (best when the budget runs out), and whichever
+// cargo is expected to make more is kept
+func planLoads(plan *model.TradePlan) []*model.TradePlanLoad {
+	loads := make([]*model.TradePlanLoad, 0)
+	byDestination := make(map[string][]*model.TradePlanPair)
+
+	//pairs are already in order of expected profit per ton
+	destinations := make([]string, 0)
+	for _, p := range plan.Pairs {
+		if _, ok := byDestination[p.Destination]; !ok {
+			destinations = append(destinations, p.Destination)
+		}
+		if p.ExpectedProfit > 0 {
+			byDestination[p.Destination] = append(byDestination[p.Destination], p)
+		} else if _, ok := byDestination[p.Destination]; !ok {
+			byDestination[p.Destination] = make([]*model.TradePlanPair, 0)
+		}
+	}
+
+	for _, d := range destinations {
+		perTon := byDestination[d]
+		perCredit := make([]*model.TradePlanPair, len(perTon))
+		copy(perCredit, perTon)
+		sort.SliceStable(perCredit, func(i, j int) bool {
+			return perCredit[i].ExpectedProfit*h.MaxInt(perCredit[j].MeanPurchase, 1) > perCredit[j].ExpectedProfit*h.MaxInt(perCredit[i].MeanPurchase, 1)
+		})
+
+		load := fillLoad(plan, perTon)
+		if byCredit := fillLoad(plan, perCredit); byCredit.ExpectedProfit > load.ExpectedProfit {
+			load = byCredit
+		}
+		load.Destination = d
+		for _, p := range plan.Pairs {
+			if p.Destination == d {
+				load.Parsecs = p.Parsecs
+				break
+			}
+		}
+		loads = append(loads, load)
+	}
+
+	sort.SliceStable(loads, func(i, j int) bool {
+		return loads[i].ExpectedProfit > loads[j].ExpectedProfit
+	})
+	return loads
+}
+
+// fillLoad takes as many tons of each of the goods in turn as the hold and the budget allow
+func fillLoad(plan *model.TradePlan, pairs []*model.TradePlanPair) *model.TradePlanLoad {
+	load := &model.TradePlanLoad{Cargo: make([]*model.TradePlanCargo, 0)}
+
+	for _, p := range pairs {
+		costPerTon := p.MeanPurchase + p.MeanPurchase*plan.BrokerFeePercent/100
+		tons := p.TonsAvail
+		if plan.CargoLimited {
+			tons = h.MinInt(tons, plan.CargoTons-load.Tons)
+		}
+		if plan.BudgetLimited && costPerTon > 0 {
+			tons = h.MinInt(tons, (plan.Budget-load.Cost)/costPerTon)
+		}
+		if tons <= 0 {
+			continue
+		}
+
+		load.Cargo = append(load.Cargo, &model.TradePlanCargo{
+			LotId:          p.LotId,
+			Goods:          p.Goods,
+			Tons:           tons,
+			Cost:           tons * costPerTon,
+			ExpectedProfit: tons * p.ExpectedProfit,
+		})
+		load.Tons += tons
+		load.Cost += tons * costPerTon
+		load.ExpectedProfit += tons * p.ExpectedProfit
+	}
+	return load
+}
+
+func tradeGoodByType(tradeGoodsMap model.TradeGoodsMap, goodsType string) (*model.TradeGood, bool) {
+	for _, value := range tradeGoodsMap.Values() {
+		if tradeGoodsMap[value].Type == goodsType {
+			return tradeGoodsMap[value], true
+		}
+	}
+	return nil, false
+}
+
+func writePlanOutput(ctx *util.TASContext, plan *model.TradePlan) {
+	var sb strings.Builder
+
+	sb.WriteString("Speculative Trade Plan from " + plan.World)
+	broker := fmt.Sprintf("Broker %d", plan.BrokerSkill)
+	if plan.HiredBroker {
+		broker = fmt.Sprintf("hired broker with Broker %d, taking %d%%", plan.BrokerSkill, plan.BrokerFeePercent)
+	}
+	sb.WriteString(h.NL + fmt.Sprintf("Worlds within jump-%d, %s, %d trials per pair", plan.JumpRange, broker, plan.Trials))
+	hold := "not limited"
+	if plan.CargoLimited {
+		hold = fmt.Sprintf("%dT", plan.CargoTons)
+	}
+	sb.WriteString(h.NL + "Cargo Hold:" + h.SP + hold)
+	budget := "not limited"
+	if plan.BudgetLimited {
+		budget = fmt.Sprintf("Cr%d", plan.Budget)
+	}
+	sb.WriteString(h.NL + "Budget:" + h.SP + budget)
+
+	sb.WriteString(h.NL)
+	sb.WriteString(h.NL + "Best Goods and Destinations (per ton)")
+	for _, p := range plan.Pairs[:h.MinInt(len(plan.Pairs), pairsShown)] {
+		sb.WriteString(h.NL + h.TAB + fmt.Sprintf("%s (lot %d) to %s (%d parsecs): expected profit Cr%d, std dev Cr%d, loses money %d%% of the time",
+			p.Goods, p.LotId, p.Destination, p.Parsecs, p.ExpectedProfit, p.ProfitStdDev, p.LossPercent))
+		sb.WriteString(h.NL + h.TAB + h.TAB + fmt.Sprintf("%dT available, buying at Cr%d and selling at Cr%d on average", p.TonsAvail, p.MeanPurchase, p.MeanSale))
+	}
+
+	sb.WriteString(h.NL)
+	sb.WriteString(h.NL + "Best Cargo by Destination")
+	for _, l := range plan.Loads {
+		if len(l.Cargo) == 0 {
+			reason := "nothing is expected to make a profit"
+			if profitableTo(plan, l.Destination) {
+				reason = "the hold or the budget can't take any of the goods expected to make a profit"
+			}
+			sb.WriteString(h.NL + h.TAB + fmt.Sprintf("%s (%d parsecs): %s", l.Destination, l.Parsecs, reason))
+			continue
+		}
+		sb.WriteString(h.NL + h.TAB + fmt.Sprintf("%s (%d parsecs): %dT costing Cr%d, expected profit Cr%d", l.Destination, l.Parsecs, l.Tons, l.Cost, l.ExpectedProfit))
+		for _, c := range l.Cargo {
+			sb.WriteString(h.NL + h.TAB + h.TAB + fmt.Sprintf("%dT of %s (lot %d) for Cr%d, expected profit Cr%d", c.Tons, c.Goods, c.LotId, c.Cost, c.ExpectedProfit))
+		}
+	}
+
+	sb.WriteString(h.NL)
+	for _, n := range plan.Notes {
+		sb.WriteString(h.NL + n)
+	}
+
+	fmt.Println(sb.String())
+
+	//also write to file if requested
+	writeToFile, _ := ctx.Config().Flags.GetBool(util.ToFileFlagName)
+	if writeToFile {
+		h.WrappedJSONFileWriter(ctx, plan, plan.ToFileName())
+	}
+}
+
+// profitableTo is true if any of the goods is expected to make a profit at the destination
+func profitableTo(plan *model.TradePlan, destination string) bool {
+	for _, p := range plan.Pairs {
+		if p.Destination == destination && p.ExpectedProfit > 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+package trade
+
+import (
+	"testing"
+
+	"tas/internal/model"
+	"tas/internal/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// expectedPricePercent is the exact mean percentage of the base price for 3D + dm on the Modified Price table
+func expectedPricePercent(dm int, isBuying bool) float64 {
+	sum, count := 0, 0
+	for a := 1; a <= 6; a++ {
+		for b := 1; b <= 6; b++ {
+			for c := 1; c <= 6; c++ {
+				sum += modifiedPricePercent(a+b+c+dm, isBuying)
+				count++
+			}
+		}
+	}
+	return float64(sum) / float64(count)
+}
+
+func TestSimulatePair(t *testing.T) {
+
+	//a DM this large always rolls the best result on the table, so every trial is the same
+	pair := &model.TradePlanPair{BasePrice: 1000, PurchaseDM: 30, SaleDM: 30}
+	simulatePair(util.NewDice(1), pair, 100, 0, 0)
+	assert.Equal(t, 150, pair.MeanPurchase)
+	assert.Equal(t, 4000, pair.MeanSale)
+	assert.Equal(t, 3850, pair.ExpectedProfit)
+	assert.Equal(t, 0, pair.ProfitVariance)
+	assert.Equal(t, 0, pair.LossPercent)
+
+	//a hired broker's fee is taken from both prices
+	simulatePair(util.NewDice(1), pair, 100, 0, 10)
+	assert.Equal(t, 4000-150-415, pair.ExpectedProfit)
+
+	//with ordinary DMs the mean prices are close to the exact means worked out from the table
+	pair = &model.TradePlanPair{BasePrice: 1000, PurchaseDM: 1, SaleDM: -1}
+	simulatePair(util.NewDice(42), pair, 20000, 2, 0)
+	assert.InDelta(t, expectedPricePercent(3, true)*10, pair.MeanPurchase, 10)
+	assert.InDelta(t, expectedPricePercent(1, false)*10, pair.MeanSale, 10)
+	assert.InDelta(t, pair.MeanSale-pair.MeanPurchase, pair.ExpectedProfit, 1)
+	assert.Greater(t, pair.ProfitStdDev, 0)
+	assert.Greater(t, pair.LossPercent, 0)
+}
+
+func TestPlanLoads(t *testing.T) {
+
+	//pairs are in order of expected profit per ton, as GenerateTradePlan leaves them
+	pairs := func() []*model.TradePlanPair {
+		return []*model.TradePlanPair{
+			{LotId: 1, Goods: "dear", Destination: "alpha", Parsecs: 1, TonsAvail: 10, MeanPurchase: 1000, ExpectedProfit: 100},
+			{LotId: 2, Goods: "cheap", Destination: "alpha", Parsecs: 1, TonsAvail: 100, MeanPurchase: 100, ExpectedProfit: 60},
+			{LotId: 1, Goods: "dear", Destination: "beta", Parsecs: 2, TonsAvail: 10, MeanPurchase: 1000, ExpectedProfit: 0},
+			{LotId: 2, Goods: "cheap", Destination: "beta", Parsecs: 2, TonsAvail: 100, MeanPurchase: 100, ExpectedProfit: -50},
+		}
+	}
+
+	//when the budget runs out first, the goods that make the most per credit are taken
+	plan := &model.TradePlan{Pairs: pairs(), CargoTons: 100, CargoLimited: true, Budget: 2000, BudgetLimited: true}
+	loads := planLoads(plan)
+	assert.Len(t, loads, 2)
+	assert.Equal(t, "alpha", loads[0].Destination)
+	assert.Equal(t, 1, loads[0].Parsecs)
+	assert.Len(t, loads[0].Cargo, 1)
+	assert.Equal(t, "cheap", loads[0].Cargo[0].Goods)
+	assert.Equal(t, 20, loads[0].Tons)
+	assert.Equal(t, 2000, loads[0].Cost)
+	assert.Equal(t, 1200, loads[0].ExpectedProfit)
+
+	//when the hold runs out first, the goods that make the most per ton are taken
+	plan = &model.TradePlan{Pairs: pairs(), CargoTons: 5, CargoLimited: true}
+	loads = planLoads(plan)
+	assert.Equal(t, "dear", loads[0].Cargo[0].Goods)
+	assert.Equal(t, 5, loads[0].Tons)
+	assert.Equal(t, 500, loads[0].ExpectedProfit)
+
+	//with neither limited, all of both goods are taken
+	plan = &model.TradePlan{Pairs: pairs()}
+	loads = planLoads(plan)
+	assert.Equal(t, 110, loads[0].Tons)
+	assert.Equal(t, 7000, loads[0].ExpectedProfit)
+
+	//goods that make no profit, or lose money, are never taken
+	assert.Equal(t, "beta", loads[1].Destination)
+	assert.Equal(t, 2, loads[1].Parsecs)
+	assert.Empty(t, loads[1].Cargo)
+	assert.Equal(t, 0, loads[1].ExpectedProfit)
+
+	//a full hold takes nothing
+	plan = &model.TradePlan{Pairs: pairs(), CargoTons: 0, CargoLimited: true}
+	loads = planLoads(plan)
+	assert.Empty(t, loads[0].Cargo)
+	assert.Empty(t, loads[1].Cargo)
+}
@@ -0,0 +1,115 @@
+package trade
+
+import (
+	"fmt"
+
+	"tas/internal/model"
+	"tas/internal/util"
+)
+
+/*
+	By default only the DMs for each lot are given and the price is rolled at the table. If the Broker skill of the
+	party, or of a broker they hire, is given then the price of every lot is rolled on the Modified Price table
+	(pg 243) and the actual price, the cost of the full lot and any broker fee are worked out
+*/
+
+const (
+	BrokerFlagName      = "broker"
+	HiredBrokerFlagName = "hired-broker"
+	BrokerFeeFlagName   = "broker-fee"
+
+	DefaultBrokerFeePercent = 10
+
+	hiredBrokerDM = 2
+)
+
+// Modified Price table (pg 243), indexed by the result of 3D + DMs from the lowest result on the table
+var modifiedPriceTable = []struct{ purchase, sale int }{
+	{300, 10}, {250, 20}, {200, 30}, {175, 40}, {150, 45}, {135, 50}, {125, 55}, {120, 60}, {115, 65},
+	{110, 70}, {105, 75}, {100, 80}, {95, 85}, {90, 90}, {85, 100}, {80, 105}, {75, 110}, {70, 115},
+	{65, 120}, {60, 125}, {55, 130}, {50, 140}, {45, 150}, {40, 160}, {35, 175}, {30, 200}, {25, 250},
+	{20, 300}, {15, 400},
+}
+
+const lowestModifiedPriceResult = -3
+
+// priceBroker is the Broker skill used for price rolls, and whether it belongs to a hired broker. It is false if
+// no skill was given, in which case prices are not resolved
+func priceBroker(ctx *util.TASContext) (int, bool, bool, error) {
+
+	flags := ctx.Config().Flags
+	partyGiven := flags.Changed(BrokerFlagName)
+	hiredGiven := flags.Changed(HiredBrokerFlagName)
+
+	switch {
+	case partyGiven && hiredGiven:
+		return 0, false, false, fmt.Errorf("give either the party's Broker skill (--%s) or a hired broker's (--%s), not both", BrokerFlagName, HiredBrokerFlagName)
+	case partyGiven:
+		skill, _ := flags.GetInt(BrokerFlagName)
+		return skill, false, true, nil
+	case hiredGiven:
+		skill, _ := flags.GetInt(HiredBrokerFlagName)
+		return skill, true, true, nil
+	}
+	return 0, false, false, nil
+}
+
+// modifiedPricePercent is the percentage of the base price paid for a result on the Modified Price table
+func modifiedPricePercent(result int, isBuying bool) int {
+	i := result - lowestModifiedPriceResult
+	if i < 0 {
+		i = 0
+	}
+	if i >= len(modifiedPriceTable) {
+		i = len(modifiedPriceTable) - 1
+	}
+	if isBuying {
+		return modifiedPriceTable[i].purchase
+	}
+	return modifiedPriceTable[i].sale
+}
+
+// ResolvePrices rolls 3D + the Broker skill + the lot's DM for every lot, and works out the price per ton from the
+// Modified Price table. When buying, the cost of the whole lot is worked out too. A hired broker adds a flat DM+2
+// but takes a fee of the given percentage of the price. The rolls use their own dice so the lots are unchanged
+func ResolvePrices(ctx *util.TASContext, summary *model.SpeculativeTradeSummary, skill int, hiredBroker bool, feePercent int, isBuying bool) {
+
+	log := ctx.Logger()
+	log.Info().Msg("Resolving speculative trade prices...")
+
+	dice := util.NewDice(util.DeriveSeed(ctx.Dice().Seed(), "spec-prices"))
+
+	brokerDM := skill
+	if hiredBroker {
+		brokerDM += hiredBrokerDM
+		summary.BrokerFeePercent = feePercent
+	}
+	summary.BrokerSkill = skill
+	summary.HiredBroker = hiredBroker
+
+	for _, l := range summary.TradeLots {
+		l.PriceRoll = dice.Sum(3) + brokerDM + l.OfferPriceDM
+		l.PricePercent = modifiedPricePercent(l.PriceRoll, isBuying)
+		l.PricePerTon = l.BasePrice * l.PricePercent / 100
+
+		//when selling the tons are not known, so the fee is given per ton
+		if isBuying {
+			l.LotPrice = l.PricePerTon * l.TonsAvail
+		}
+		if hiredBroker {
+			if isBuying {
+				l.BrokerFee = l.LotPrice * feePercent / 100
+			} else {
+				l.BrokerFee = l.PricePerTon * feePercent / 100
+			}
+		}
+	}
+
+	note := fmt.Sprintf("Prices were rolled on the Modified Price table (pg 243) using 3D + each lot's DM + the party's Broker skill of %d.", skill)
+	if hiredBroker {
+		note = fmt.Sprintf("Prices were rolled on the Modified Price table (pg 243) using 3D + each lot's DM + a hired broker's Broker skill of %d + DM%+d. The broker takes %d%% of the price.", skill, hiredBrokerDM, feePercent)
+	}
+	summary.TradeNotes = append(summary.TradeNotes, note)
+
+	log.Info().Msg("Speculative trade prices resolved")
+}
@@ -0,0 +1,84 @@
+package trade
+
+import (
+	"testing"
+
+	"tas/internal/model"
+	"tas/internal/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestModifiedPricePercent(t *testing.T) {
+
+	tests := []struct {
+		result   int
+		purchase int
+		sale     int
+	}{
+		{-10, 300, 10}, //below the table is the lowest row
+		{-3, 300, 10},
+		{-2, 250, 20},
+		{0, 175, 40},
+		{8, 100, 80},
+		{11, 85, 100},
+		{24, 20, 300},
+		{25, 15, 400},
+		{40, 15, 400}, //above the table is the highest row
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.purchase, modifiedPricePercent(tt.result, true), "purchase for %d", tt.result)
+		assert.Equal(t, tt.sale, modifiedPricePercent(tt.result, false), "sale for %d", tt.result)
+	}
+}
+
+func TestResolvePrices(t *testing.T) {
+
+	ctx := util.NewContext().WithLogger(util.NewLogger("off")).WithDice(1)
+
+	//a DM this large always rolls the best result on the table, so the prices are known
+	tests := []struct {
+		name          string
+		hiredBroker   bool
+		isBuying      bool
+		pricePercent  int
+		pricePerTon   int
+		lotPrice      int
+		brokerFee     int
+		summaryFeePct int
+	}{
+		{"party buying", false, true, 15, 150, 3000, 0, 0},
+		{"party selling", false, false, 400, 4000, 0, 0, 0},
+		{"hired broker buying", true, true, 15, 150, 3000, 300, 10},  //the fee is for the whole lot
+		{"hired broker selling", true, false, 400, 4000, 0, 400, 10}, //the fee is per ton
+	}
+	for _, tt := range tests {
+		lot := &model.SpeculativeTradeLot{TonsAvail: 20, BasePrice: 1000, OfferPriceDM: 30}
+		summary := &model.SpeculativeTradeSummary{TradeLots: []*model.SpeculativeTradeLot{lot}}
+		ResolvePrices(ctx, summary, 1, tt.hiredBroker, 10, tt.isBuying)
+
+		assert.Equal(t, tt.pricePercent, lot.PricePercent, tt.name)
+		assert.Equal(t, tt.pricePerTon, lot.PricePerTon, tt.name)
+		assert.Equal(t, tt.lotPrice, lot.LotPrice, tt.name)
+		assert.Equal(t, tt.brokerFee, lot.BrokerFee, tt.name)
+		assert.Equal(t, tt.summaryFeePct, summary.BrokerFeePercent, tt.name)
+		assert.Equal(t, tt.hiredBroker, summary.HiredBroker, tt.name)
+		assert.Len(t, summary.TradeNotes, 1, tt.name)
+	}
+
+	//the roll is 3D + the skill + the lot's DM, with DM+2 more for a hired broker
+	for _, hired := range []bool{false, true} {
+		lot := &model.SpeculativeTradeLot{TonsAvail: 20, BasePrice: 1000, OfferPriceDM: -1}
+		summary := &model.SpeculativeTradeSummary{TradeLots: []*model.SpeculativeTradeLot{lot}}
+		ResolvePrices(ctx, summary, 1, hired, 10, true)
+
+		brokerDM := 1
+		if hired {
+			brokerDM += hiredBrokerDM
+		}
+		assert.GreaterOrEqual(t, lot.PriceRoll, 3+brokerDM-1)
+		assert.LessOrEqual(t, lot.PriceRoll, 18+brokerDM-1)
+		assert.Equal(t, modifiedPricePercent(lot.PriceRoll, true), lot.PricePercent)
+		assert.Equal(t, lot.BasePrice*lot.PricePercent/100, lot.PricePerTon)
+	}
+}
@@ -0,0 +1,65 @@
+package trade
+
+import (
+	"errors"
+	"os"
+
+	h "tas/internal/cmd/helpers"
+	"tas/internal/model"
+	"tas/internal/util"
+)
+
+const (
+	ShipFileFlagName    = "ship"
+	DefaultShipFilename = "ship.json"
+)
+
+// LoadShipProfile reads the ship profile from data-local. If no ship file was asked for and the default file is not
+// there, it returns nil so that the character data in the trade data file is used instead
+func LoadShipProfile(ctx *util.TASContext) (*model.ShipProfile, error) {
+	log := ctx.Logger()
+
+	shipFilename, err := ctx.Config().Flags.GetString(ShipFileFlagName)
+	if err != nil {
+		shipFilename = DefaultShipFilename
+	}
+	shipFilenameWithPath := "data-local/" + shipFilename
+
+	if !ctx.Config().Flags.Changed(ShipFileFlagName) {
+		if _, err := os.Stat(shipFilenameWithPath); errors.Is(err, os.ErrNotExist) {
+			log.Info().Msg("no ship profile found, using the character data from the trade data file")
+			return nil, nil
+		}
+	}
+
+	log.Info().Str("filename", shipFilenameWithPath).Msg("loading ship profile")
+	fileData := util.IngestFiles("", []string{shipFilenameWithPath})
+	fd := fileData[shipFilenameWithPath]
+	if !fd.Ok() {
+		log.Error().Err(fd.Err).Str("filename", fd.Name).Msg("unable to open ship profile")
+		return nil, errors.New(h.UnableToContinueBecauseOfErrors)
+	}
+
+	ship, err := model.ShipProfileFromFile(fd.Data)
+	if err != nil {
+		log.Error().Err(err).Str("filename", fd.Name).Msg("unable to parse ship profile")
+		return nil, errors.New(h.UnableToContinueBecauseOfErrors)
+	}
+
+	errs := ship.Validate()
+	if len(errs) > 0 {
+		log.Error().Msg("ship profile is not valid. See the following lines for more information")
+		for _, e := range errs {
+			log.Error().Err(e).Send()
+		}
+		return nil, errors.New(h.UnableToContinueBecauseOfErrors)
+	}
+
+	if ship.JumpRange() < 1 {
+		log.Warn().Int("jump-rating", ship.JumpRating).Int("fuel-tons", ship.FuelTons).Msg(model.ErrCannotJump.Error())
+	} else if ship.JumpRange() < ship.JumpRating {
+		log.Warn().Int("jump-rating", ship.JumpRating).Int("fuel-tons", ship.FuelTons).Msgf("the ship only carries fuel for jump-%d", ship.JumpRange())
+	}
+
+	return ship, nil
+}
@@ -6,6 +6,7 @@ import (
 	"sort"
 	"strings"
 	h "tas/internal/cmd/helpers"
+	"tas/internal/cmd/world"
 	"tas/internal/model"
 	"tas/internal/util"
 
@@ -24,14 +25,14 @@ var SpecTradeCmdConfig = &cobra.Command{
 	Run:   specTradeCmd,
 
 	Args: func(cmd *cobra.Command, args []string) error {
-		if len(args) != 2 {
-			return fmt.Errorf("exactly 2 arguments required - the current world name, and and either 'buy' or 'sell")
+		if len(args) != 1 && len(args) != 2 {
+			return fmt.Errorf("1 or 2 arguments required - the current world name (the ledger's current world if not given), and either 'buy' or 'sell")
 		}
 
-		operation := args[1]
+		operation := args[len(args)-1]
 		operation = strings.ToLower(strings.TrimSpace(operation))
 		if operation != "buy" && operation != "sell" {
-			return fmt.Errorf("second argument must be 'buy' or 'sell'")
+			return fmt.Errorf("last argument must be 'buy' or 'sell'")
 		}
 		return nil
 	},
@@ -55,7 +56,7 @@ func specTradeCmd(cmd *cobra.Command, args []string) {
 	log := util.NewLogger(loglevel)
 	ctx := util.NewContext().
 		WithLogger(log).
-		WithDice().
+		WithDice(cfg.Seed()).
 		WithConfig(cfg)
 
 	//load the data we need to build speculative trade data
@@ -65,9 +66,15 @@ func specTradeCmd(cmd *cobra.Command, args []string) {
 	}
 
 	//fetch the arguments (local world name)  and operation and do the generation
-	localWorldName := args[0]
+	localWorldName := ""
+	if len(args) == 2 {
+		localWorldName = args[0]
+	} else if localWorldName, err = ledgerWorld(ctx); err != nil {
+		log.Error().Err(err).Msg("unable to find the current world")
+		return
+	}
 	isBuying := true
-	if args[1] == "sell" {
+	if strings.ToLower(strings.TrimSpace(args[len(args)-1])) == "sell" {
 		isBuying = false
 	}
 
@@ -80,8 +87,26 @@ func specTradeCmd(cmd *cobra.Command, args []string) {
 
 	log.Debug().Bool("isBuying", isBuying).Str("world", localWorldName).Send()
 
+	//prices are only rolled if a Broker skill was given
+	brokerSkill, hiredBroker, resolvePrices, err := priceBroker(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("unable to resolve trade prices")
+		return
+	}
+
 	summary := GenerateSpeculativeTrade(ctx, localData, tradeGoodsMap, isBuying)
 	summary.WorldName = localWorldName
+
+	//at the ledger's current world, the search for a supplier starts on the ledger's date
+	if len(args) == 1 {
+		if date, ok := ledgerDate(ctx); ok {
+			summary.SupplierFoundDate = date.AddDays(summary.SupplierSearchDays).String()
+		}
+	}
+	if resolvePrices {
+		feePercent, _ := cfg.Flags.GetInt(BrokerFeeFlagName)
+		ResolvePrices(ctx, &summary, brokerSkill, hiredBroker, feePercent, isBuying)
+	}
 	writeSpeculativeOutput(ctx, summary, isBuying)
 }
 
@@ -103,7 +128,7 @@ func GenerateSpeculativeTrade(ctx *util.TASContext, localData *model.WorldTradeI
 
 	//notes
 	var notes = []string{"see pg 241.",
-		"Choose an option at bottom of pg 241 to find a Supplier or Broker. Use provided DM for this check. Depending on method used, this takes some time.",
+		"Choose an option at bottom of pg 241 to find a Supplier or Broker. Use provided DM for this check. The search takes 1D days, which is rolled for you.",
 		"Dealing with a Broker adds a flat DM+2 to all price rolls plus uses the Broker's Broker or Streetwise skill (2D/3) instead of the players', but costs 10-20% of the total order price",
 		"For each Lot of Trade Goods, determine a Purchase Price by: using the DM and Base Price provided for that lot, the players' (or Broker's) Broker skill and a roll of 3D. Consult table pg 243",
 		"The same process must be done for selling goods to determine the price a purchaser is willing to pay",
@@ -112,7 +137,9 @@ func GenerateSpeculativeTrade(ctx *util.TASContext, localData *model.WorldTradeI
 	}
 
 	summary := model.SpeculativeTradeSummary{
+		Seed:                   ctx.Dice().Seed(),
 		FindSupplierOrBrokerDM: findSupplierBrokerDM,
+		SupplierSearchDays:     SupplierSearchDays(ctx),
 		TradeNotes:             notes,
 	}
 
@@ -138,6 +165,7 @@ func writeSpeculativeOutput(ctx *util.TASContext, summary model.SpeculativeTrade
 
 		sb.WriteString(h.NL)
 		sb.WriteString(h.NL + "DM to Find Supplier or Broker to Aid in Purchase:" + h.SP + fmt.Sprintf("%d", summary.FindSupplierOrBrokerDM))
+		writeSupplierSearch(&sb, summary)
 		sb.WriteString(h.NL)
 		sb.WriteString(h.NL + "Trade Lots Available For Purchase")
 		for _, l := range summary.TradeLots {
@@ -147,12 +175,14 @@ func writeSpeculativeOutput(ctx *util.TASContext, summary model.SpeculativeTrade
 			sb.WriteString(h.NL + h.TAB + h.TAB + "Base Price:" + h.SP + fmt.Sprintf("%d", l.BasePrice))
 			sb.WriteString(h.NL + h.TAB + h.TAB + "Tons Available:" + h.SP + fmt.Sprintf("%d", l.TonsAvail))
 			sb.WriteString(h.NL + h.TAB + h.TAB + "Purchase Price DM:" + h.SP + fmt.Sprintf("%d", l.OfferPriceDM))
+			writeLotPrice(&sb, summary, l, isBuying)
 		}
 	} else {
 		sb.WriteString("Speculative Trade Offerings - Characters are Selling Goods")
 
 		sb.WriteString(h.NL)
 		sb.WriteString(h.NL + "DM to Find Supplier or Broker to Aid in Sale:" + h.SP + fmt.Sprintf("%d", summary.FindSupplierOrBrokerDM))
+		writeSupplierSearch(&sb, summary)
 		sb.WriteString(h.NL)
 		sb.WriteString(h.NL + "Sale of Goods Owned - Trade Table")
 		for _, l := range summary.TradeLots {
@@ -160,6 +190,7 @@ func writeSpeculativeOutput(ctx *util.TASContext, summary model.SpeculativeTrade
 			sb.WriteString(h.NL + h.TAB + h.TAB + "Type of Goods:" + h.SP + l.Type)
 			sb.WriteString(h.NL + h.TAB + h.TAB + "Base Price:" + h.SP + fmt.Sprintf("%d", l.BasePrice))
 			sb.WriteString(h.NL + h.TAB + h.TAB + "Sale Price DM:" + h.SP + fmt.Sprintf("%d", l.OfferPriceDM))
+			writeLotPrice(&sb, summary, l, isBuying)
 		}
 	}
 	sb.WriteString(h.NL)
@@ -176,6 +207,24 @@ func writeSpeculativeOutput(ctx *util.TASContext, summary model.SpeculativeTrade
 	}
 }
 
+func writeLotPrice(sb *strings.Builder, summary model.SpeculativeTradeSummary, l *model.SpeculativeTradeLot, isBuying bool) {
+	if l.PricePercent == 0 { //prices were not resolved
+		return
+	}
+
+	sb.WriteString(h.NL + h.TAB + h.TAB + "Price:" + h.SP + fmt.Sprintf("Cr%d per ton (%d%% of base price, roll %d)", l.PricePerTon, l.PricePercent, l.PriceRoll))
+	if isBuying {
+		sb.WriteString(h.NL + h.TAB + h.TAB + "Full Lot Cost:" + h.SP + fmt.Sprintf("Cr%d", l.LotPrice))
+	}
+	if summary.HiredBroker {
+		perTon := ""
+		if !isBuying {
+			perTon = " per ton"
+		}
+		sb.WriteString(h.NL + h.TAB + h.TAB + "Broker Fee:" + h.SP + fmt.Sprintf("Cr%d%s (%d%%)", l.BrokerFee, perTon, summary.BrokerFeePercent))
+	}
+}
+
 func generateTradeLots(ctx *util.TASContext, localData *model.WorldTradeInfo, tradeGoodsMap model.TradeGoodsMap, isBuying bool) []*model.SpeculativeTradeLot {
 
 	log := ctx.Logger()
@@ -198,7 +247,8 @@ func generateTradeLots(ctx *util.TASContext, localData *model.WorldTradeInfo, tr
 	//First Step: Look through this map and generate a (potential) Lot for all Common Goods
 	//and each Advanced and Illegal Good that applies per the world's trade codes
 	log.Debug().Msg("starting first pass lot creation")
-	for _, dataRow := range tradeGoodsMap {
+	for _, value := range tradeGoodsMap.Values() {
+		dataRow := tradeGoodsMap[value]
 		log.Debug().Str("type", dataRow.Type).Msg("attempting creation of a new trade lot")
 		newLot, success := buildTradeLot(ctx, availabilityDM, localData, dataRow, true, isBuying)
 
@@ -340,10 +390,10 @@ func calculatePriceDM(localData *model.WorldTradeInfo, dataRow *model.TradeGood,
 	//fetch world trade codes, augmented by Travel Zone information
 	worldTradeCodes := localData.TradeCodes
 	if localData.ZoneAmber {
-		worldTradeCodes["Amber Zone"] = struct{}{}
+		worldTradeCodes[model.TradeDMAmberZone] = struct{}{}
 	}
 	if localData.ZoneRed {
-		worldTradeCodes["Red Zone"] = struct{}{}
+		worldTradeCodes[model.TradeDMRedZone] = struct{}{}
 	}
 
 	//determine highest purchase price DM for world trade code. -10 here allows for the highest offset to be lower than 0
@@ -474,8 +524,21 @@ func LoadSpeculativeTradeFacts(ctx *util.TASContext) (*model.TradeFacts, model.T
 		return nil, nil, errors.New(h.UnableToContinueBecauseOfErrors)
 	}
 
+	//trade codes on worlds and goods both come from the trade code catalogue
+	tradeCodes, err := world.LoadTradeCodes(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if errs := tradeGoods.UseCatalogueCodes(tradeCodes); len(errs) > 0 {
+		log.Error().Msg("trade goods data is not valid. See the following lines for more information")
+		for _, e := range errs {
+			log.Error().Err(e).Send()
+		}
+		return nil, nil, errors.New(h.UnableToContinueBecauseOfErrors)
+	}
+
 	//parse the trade data and do further, detailed validation
-	hasErrors, errs := tradeFacts.Parse()
+	hasErrors, errs := tradeFacts.Parse(tradeCodes)
 	if hasErrors { //Parse truly failed with real errors
 		log.Error().Msg("parse of world UWP data failed. The following lines should point you to the problem")
 		for _, e := range errs {
@@ -492,3 +555,10 @@ func LoadSpeculativeTradeFacts(ctx *util.TASContext) (*model.TradeFacts, model.T
 	log.Info().Msg("parsing trade data files complete")
 	return tradeFacts, tradeGoods, nil
 }
+
+func writeSupplierSearch(sb *strings.Builder, summary model.SpeculativeTradeSummary) {
+	sb.WriteString(h.NL + "Days to Find Supplier or Broker:" + h.SP + fmt.Sprintf("%d", summary.SupplierSearchDays))
+	if summary.SupplierFoundDate != "" {
+		sb.WriteString(" (found on " + summary.SupplierFoundDate + ")")
+	}
+}
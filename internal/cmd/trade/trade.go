@@ -6,6 +6,8 @@ import (
 	"strings"
 
 	h "tas/internal/cmd/helpers"
+	"tas/internal/cmd/world"
+	"tas/internal/hexgrid"
 	"tas/internal/model"
 	"tas/internal/util"
 
@@ -17,6 +19,8 @@ const (
 	tradeGoodFilename        = "trade-goods.json"
 
 	TradeFileFlagName = "file"
+
+	mailLotTons = 5
 )
 
 var TradeCmdConfig = &cobra.Command{
@@ -26,8 +30,8 @@ var TradeCmdConfig = &cobra.Command{
 	Run:   tradeCmd,
 
 	Args: func(cmd *cobra.Command, args []string) error {
-		if len(args) != 2 {
-			return fmt.Errorf("exactly 2 arguments required - the source and destination world names")
+		if len(args) != 1 && len(args) != 2 {
+			return fmt.Errorf("1 or 2 arguments required - the source (the ledger's current world if not given) and destination world names")
 		}
 		return nil
 	},
@@ -51,7 +55,7 @@ func tradeCmd(cmd *cobra.Command, args []string) {
 	log := util.NewLogger(loglevel)
 	ctx := util.NewContext().
 		WithLogger(log).
-		WithDice().
+		WithDice(cfg.Seed()).
 		WithConfig(cfg)
 
 	//load the data we need to build standard trade data
@@ -62,8 +66,11 @@ func tradeCmd(cmd *cobra.Command, args []string) {
 	}
 
 	//fetch the arguments (from/to world names) and do the generation
-	from := args[0]
-	to := args[1]
+	from, to, err := tripWorlds(ctx, args)
+	if err != nil {
+		log.Error().Err(err).Msg("unable to find the source world")
+		return
+	}
 
 	summary, err := GenerateStandardTrade(ctx, from, to, tradeFacts)
 	if err != nil {
@@ -71,6 +78,56 @@ func tradeCmd(cmd *cobra.Command, args []string) {
 		return
 	}
 
+	//travel time is only known if the distance is
+	if summary.JumpsRequired > 0 {
+		sizes, err := world.LoadWorldSizes(ctx)
+		if err != nil {
+			return
+		}
+		fromData, _ := tradeFacts.DataForWorldName(from)
+		toData, _ := tradeFacts.DataForWorldName(to)
+		summary.Travel = TravelTime(ctx, fromData, toData, summary.JumpsRequired, tradeFacts.ShipProfile(), sizes)
+
+		//a trip from the ledger's current world leaves on the ledger's date
+		if len(args) == 1 {
+			if date, ok := ledgerDate(ctx); ok {
+				summary.Departure = date.String()
+				summary.Arrival = date.AddHours(summary.Travel.TotalHours).String()
+			}
+		}
+	}
+
+	//the cargo space for this trip, if given, replaces the ship's cargo tonnage
+	cargoTons, cargoKnown, err := cargoSpace(ctx, tradeFacts.ShipProfile())
+	if err != nil {
+		log.Error().Err(err).Msg("unable to resolve freight traffic")
+		return
+	}
+
+	//the traffic is only resolved into passengers and freight lots if the check was given
+	effect, resolve, err := trafficCheck(ctx, EffectFlagName, SkillFlagName)
+	if err != nil {
+		log.Error().Err(err).Msg("unable to resolve passenger traffic")
+		return
+	}
+	if resolve {
+		ResolvePassengers(ctx, summary, effect, tradeFacts.JumpRating(), tradeFacts.ShipProfile())
+	}
+
+	effect, resolve, err = trafficCheck(ctx, FreightEffectFlagName, FreightSkillFlagName)
+	if err != nil {
+		log.Error().Err(err).Msg("unable to resolve freight traffic")
+		return
+	}
+	if resolve {
+		ResolveFreight(ctx, summary, effect, tradeFacts.JumpRating(), cargoTons, cargoKnown)
+	}
+
+	//the mail shares the cargo space with any freight taken
+	if cargoKnown {
+		noteMailHold(summary.MailTrade, cargoTons-summary.FreightTrade.TonsCarried)
+	}
+
 	writeStandardOutput(ctx, summary)
 
 }
@@ -93,14 +150,31 @@ func GenerateStandardTrade(ctx *util.TASContext, from string, to string, tradeFa
 		return nil, err
 	}
 
+	//distance is only known if both worlds have a hex location
+	parsecs := 0
+	if fromData.Hex != nil && toData.Hex != nil {
+		parsecs = hexgrid.Distance(*fromData.Hex, *toData.Hex)
+		log.Info().Int("parsecs", parsecs).Msg("distance between worlds")
+	}
+
 	alltrade := &model.StandardTradeModifiers{
 		From:           from,
 		To:             to,
-		PassengerTrade: generatePassengers(ctx, fromData, toData, tradeFacts),
+		Seed:           ctx.Dice().Seed(),
+		Ship:           tradeFacts.ShipProfile().Name,
+		Parsecs:        parsecs,
+		PassengerTrade: generatePassengers(ctx, fromData, toData, tradeFacts, parsecs),
+	}
+	if parsecs > 0 {
+		alltrade.JumpRating = tradeFacts.JumpRating()
+		if alltrade.JumpRating < 1 {
+			return nil, model.ErrCannotJump
+		}
+		alltrade.JumpsRequired = (parsecs + alltrade.JumpRating - 1) / alltrade.JumpRating
 	}
 
 	//handle freight next, as we need the core DM to do Mail
-	coreFreightDM, freightSummary := generateFreight(ctx, fromData, toData, tradeFacts)
+	coreFreightDM, freightSummary := generateFreight(ctx, fromData, toData, tradeFacts, parsecs)
 	alltrade.FreightTrade = freightSummary
 
 	//mail
@@ -110,10 +184,57 @@ func GenerateStandardTrade(ctx *util.TASContext, from string, to string, tradeFa
 	return alltrade, nil
 }
 
+// tripWorlds is the source and destination worlds of a trip. If only the destination is given, the trip starts at
+// the current world in the campaign ledger
+func tripWorlds(ctx *util.TASContext, args []string) (string, string, error) {
+	if len(args) == 2 {
+		return args[0], args[1], nil
+	}
+	from, err := ledgerWorld(ctx)
+	return from, args[0], err
+}
+
+// ledgerWorld is the world the ship is at, from the campaign ledger
+func ledgerWorld(ctx *util.TASContext) (string, error) {
+	l, err := h.LoadLedger(ctx)
+	if err != nil {
+		return "", err
+	}
+	ctx.Logger().Info().Str("world", l.World).Msg("using the current world from the ledger")
+	return l.World, nil
+}
+
+func writeTravelTime(sb *strings.Builder, summary *model.StandardTradeModifiers) {
+	t := summary.Travel
+
+	jumps := make([]string, len(t.JumpHours))
+	for i, j := range t.JumpHours {
+		jumps[i] = fmt.Sprintf("%d", j)
+	}
+	jumpsOf := "jumps of"
+	if len(jumps) == 1 {
+		jumpsOf = "a jump of"
+	}
+	sb.WriteString(h.NL + fmt.Sprintf("Travel Time: %d days (%d hours out to the jump limit at %dG, %s %s hours, %d hours in from the jump limit)",
+		t.Days(), t.DepartureHours, t.Thrust, jumpsOf, strings.Join(jumps, " + "), t.ArrivalHours))
+	if summary.Arrival != "" {
+		sb.WriteString(h.NL + "Departing: " + summary.Departure + ", arriving: " + summary.Arrival)
+	}
+}
+
 func writeStandardOutput(ctx *util.TASContext, summary *model.StandardTradeModifiers) {
 	var sb strings.Builder
 
 	sb.WriteString("Standard Trade Offerings")
+	if summary.Ship != "" {
+		sb.WriteString(h.NL + "Ship: " + summary.Ship)
+	}
+	if summary.Parsecs > 0 {
+		sb.WriteString(h.NL + fmt.Sprintf("Distance: %d parsecs (%d jumps at jump-%d)", summary.Parsecs, summary.JumpsRequired, summary.JumpRating))
+	}
+	if summary.Travel != nil {
+		writeTravelTime(&sb, summary)
+	}
 
 	sb.WriteString(h.NL)
 	sb.WriteString(h.NL + "Passenger Trade")
@@ -126,6 +247,21 @@ func writeStandardOutput(ctx *util.TASContext, summary *model.StandardTradeModif
 	for _, pn := range summary.PassengerTrade.PassengerNotes {
 		sb.WriteString(h.NL + h.TAB + pn)
 	}
+	if len(summary.PassengerTrade.Traffic) > 0 {
+		sb.WriteString(h.NL)
+		sb.WriteString(h.NL + h.TAB + "Passengers Available" + h.SP + fmt.Sprintf("(check Effect %+d)", summary.PassengerTrade.CheckEffect))
+		for _, t := range summary.PassengerTrade.Traffic {
+			line := fmt.Sprintf("%-8s %3d passengers at Cr%d (traffic %d): Cr%d", t.PassageType, t.Passengers, t.Fare, t.TrafficValue, t.Income)
+			if t.Carried < t.Passengers {
+				line += fmt.Sprintf(" - only %d can be carried", t.Carried)
+			}
+			sb.WriteString(h.NL + h.TAB + h.TAB + line)
+		}
+		sb.WriteString(h.NL + h.TAB + h.TAB + fmt.Sprintf("Total fare income: Cr%d", summary.PassengerTrade.TotalIncome))
+		if summary.Parsecs == 0 {
+			sb.WriteString(h.NL + h.TAB + h.TAB + "The distance is unknown, so fares are for a single parsec.")
+		}
+	}
 
 	sb.WriteString(h.NL)
 	sb.WriteString(h.NL + "Freight Trade")
@@ -137,6 +273,9 @@ func writeStandardOutput(ctx *util.TASContext, summary *model.StandardTradeModif
 	for _, fn := range summary.FreightTrade.FreightNotes {
 		sb.WriteString(h.NL + h.TAB + fn)
 	}
+	if len(summary.FreightTrade.Traffic) > 0 {
+		writeFreightLots(&sb, summary)
+	}
 
 	sb.WriteString(h.NL)
 	sb.WriteString(h.NL + "Mail Service")
@@ -156,11 +295,49 @@ func writeStandardOutput(ctx *util.TASContext, summary *model.StandardTradeModif
 	}
 }
 
+func writeFreightLots(sb *strings.Builder, summary *model.StandardTradeModifiers) {
+	freight := summary.FreightTrade
+
+	sb.WriteString(h.NL)
+	sb.WriteString(h.NL + h.TAB + "Freight Available" + h.SP + fmt.Sprintf("(check Effect %+d)", freight.CheckEffect))
+	for _, t := range freight.Traffic {
+		sb.WriteString(h.NL + h.TAB + h.TAB + fmt.Sprintf("%-10s %3d lots (traffic %d)", t.LotType, t.Lots, t.TrafficValue))
+	}
+	sb.WriteString(h.NL + h.TAB + h.TAB + fmt.Sprintf("Price: Cr%d per ton", freight.PricePerTon))
+	if summary.Parsecs == 0 {
+		sb.WriteString(h.NL + h.TAB + h.TAB + "The distance is unknown, so the price is for a single parsec.")
+	}
+
+	if !freight.CargoKnown {
+		sb.WriteString(h.NL + h.TAB + h.TAB + "Lots:")
+		for _, lot := range freight.Lots {
+			sb.WriteString(h.NL + h.TAB + h.TAB + h.TAB + fmt.Sprintf("%-10s %3dT: Cr%d", lot.LotType, lot.Tons, lot.Income))
+		}
+		sb.WriteString(h.NL + h.TAB + h.TAB + fmt.Sprintf("The ship's cargo space is not known (see --%s), so no manifest was picked.", CargoFlagName))
+	} else {
+		sb.WriteString(h.NL + h.TAB + h.TAB + fmt.Sprintf("Manifest (%dT of %dT cargo space):", freight.TonsCarried, freight.CargoTons))
+		left := 0
+		for _, lot := range freight.Lots {
+			if !lot.Carried {
+				left++
+				continue
+			}
+			sb.WriteString(h.NL + h.TAB + h.TAB + h.TAB + fmt.Sprintf("%-10s %3dT: Cr%d", lot.LotType, lot.Tons, lot.Income))
+		}
+		sb.WriteString(h.NL + h.TAB + h.TAB + fmt.Sprintf("Total freight income: Cr%d", freight.TotalIncome))
+		if left > 0 {
+			sb.WriteString(h.NL + h.TAB + h.TAB + fmt.Sprintf("%d lots do not fit and are left behind.", left))
+		}
+	}
+	sb.WriteString(h.NL + h.TAB + h.TAB + freight.LatePenalty)
+}
+
 func generateMail(ctx *util.TASContext, fromData *model.WorldTradeInfo, coreFreightDM int, tradeFacts *model.TradeFacts) *model.MailTradeSummary {
 	log := ctx.Logger()
 	dice := ctx.Dice()
 
-	mailDM := tradeFacts.CharacterData.HighestScoutNavalRank + tradeFacts.CharacterData.HighestSocSkillDM
+	ship := tradeFacts.ShipProfile()
+	mailDM := ship.HighestScoutNavalRank() + ship.HighestSocDM()
 
 	log.Info().Msg("Beginning mail generation...")
 
@@ -175,7 +352,7 @@ func generateMail(ctx *util.TASContext, fromData *model.WorldTradeInfo, coreFrei
 	mailDM = h.AdjustDM(ctx, mailDM, -4, fromData.TechLevel, h.LE, 5)
 
 	//armed ship
-	mailDM = h.AdjustZoneDM(mailDM, 2, tradeFacts.CharacterData.ShipIsArmed)
+	mailDM = h.AdjustZoneDM(mailDM, 2, ship.IsArmed)
 
 	var notes = []string{"see pg 241.",
 		"Roll 2D + mail DM. On 12+, mail is entrusted to the ship and crew.",
@@ -194,7 +371,18 @@ func generateMail(ctx *util.TASContext, fromData *model.WorldTradeInfo, coreFrei
 	return summary
 }
 
-func generateFreight(ctx *util.TASContext, fromData *model.WorldTradeInfo, toData *model.WorldTradeInfo, tradeFacts *model.TradeFacts) (int, *model.FreightTradeSummary) {
+// noteMailHold notes whether the cargo space left can take the mail. Mail is all-or-nothing, so the ship can only
+// take it if every lot fits
+func noteMailHold(summary *model.MailTradeSummary, cargoTons int) {
+	mailTons := summary.LotsAvail * mailLotTons
+	if mailTons > cargoTons {
+		summary.MailNotes = append(summary.MailNotes, fmt.Sprintf("The %dT of cargo space left cannot take all %d lots (%dT), so the mail cannot be carried.", cargoTons, summary.LotsAvail, mailTons))
+	} else {
+		summary.MailNotes = append(summary.MailNotes, fmt.Sprintf("The %dT of cargo space left can take all %d lots (%dT).", cargoTons, summary.LotsAvail, mailTons))
+	}
+}
+
+func generateFreight(ctx *util.TASContext, fromData *model.WorldTradeInfo, toData *model.WorldTradeInfo, tradeFacts *model.TradeFacts, parsecs int) (int, *model.FreightTradeSummary) {
 	log := ctx.Logger()
 
 	freights := make([]*model.FreightDM, 0, 3) //3 -> major, minor, incidental
@@ -234,6 +422,9 @@ func generateFreight(ctx *util.TASContext, fromData *model.WorldTradeInfo, toDat
 	coreDM = h.AdjustDM(ctx, coreDM, 2, fromData.TechLevel, h.GE, 9)
 	coreDM = h.AdjustDM(ctx, coreDM, 2, toData.TechLevel, h.GE, 9)
 
+	//distance
+	coreDM += parsecDM(parsecs)
+
 	//major cargo
 	f := &model.FreightDM{
 		LotType: "major",
@@ -257,7 +448,7 @@ func generateFreight(ctx *util.TASContext, fromData *model.WorldTradeInfo, toDat
 
 	var notes = []string{"see pg 240.",
 		"To the given DM, add the Effect of an (8+) Broker or Streetwise check.",
-		"Use DM -1 for each parsec beyond 1 between source and destination worlds",
+		parsecNote(parsecs),
 		"Then roll on Freight Traffic table using the calculated DM to determine number of lots available in each catagory. Lots are all-or-nothing, pay on delivery.",
 		"There is a penalty for late arrival.",
 		"Freight is almost worthless in terms of its value, so absconding with Freight and not delivering it is worth less than delivering it!"}
@@ -271,7 +462,7 @@ func generateFreight(ctx *util.TASContext, fromData *model.WorldTradeInfo, toDat
 	return coreDM, summary
 }
 
-func generatePassengers(ctx *util.TASContext, fromData *model.WorldTradeInfo, toData *model.WorldTradeInfo, tradeFacts *model.TradeFacts) *model.PassengerTradeSummary {
+func generatePassengers(ctx *util.TASContext, fromData *model.WorldTradeInfo, toData *model.WorldTradeInfo, tradeFacts *model.TradeFacts, parsecs int) *model.PassengerTradeSummary {
 
 	log := ctx.Logger()
 
@@ -280,7 +471,7 @@ func generatePassengers(ctx *util.TASContext, fromData *model.WorldTradeInfo, to
 	log.Info().Msg("Beginning passenger generation...")
 
 	//DMs that apply to all berths
-	coreDM := tradeFacts.CharacterData.HighestStewardLevel
+	coreDM := tradeFacts.ShipProfile().HighestSkill("steward")
 
 	//starport - cannot use adjustDM because starport is a string, even generics wont help here
 	coreDM = h.AdjustStarportDM(coreDM, 2, fromData.Starport, "A")
@@ -305,6 +496,9 @@ func generatePassengers(ctx *util.TASContext, fromData *model.WorldTradeInfo, to
 	coreDM = h.AdjustDM(ctx, coreDM, 3, fromData.Population, h.GE, 8)
 	coreDM = h.AdjustDM(ctx, coreDM, 3, toData.Population, h.GE, 8)
 
+	//distance
+	coreDM += parsecDM(parsecs)
+
 	//High Passengers
 	p := &model.PassengerDM{
 		PassageType:  "high",
@@ -340,7 +534,7 @@ func generatePassengers(ctx *util.TASContext, fromData *model.WorldTradeInfo, to
 	//create a notes section to summarize next steps
 	var notes = []string{"see pg 239.",
 		"To the given DM, add the Effect of a (8+) Broker, Carouse or Streetwise check.",
-		"Use DM -1 for each parsec beyond 1.",
+		parsecNote(parsecs),
 		"Then roll on Passenger Traffic table using the calculated DM to determine number of available passengers at each berth level",
 	}
 
@@ -353,6 +547,21 @@ func generatePassengers(ctx *util.TASContext, fromData *model.WorldTradeInfo, to
 	return summary
 }
 
+// parsecDM is DM -1 for each parsec beyond 1 (see pg 239 - 240). An unknown distance (0) has no DM
+func parsecDM(parsecs int) int {
+	if parsecs <= 1 {
+		return 0
+	}
+	return 1 - parsecs
+}
+
+func parsecNote(parsecs int) string {
+	if parsecs == 0 {
+		return "Use DM -1 for each parsec beyond 1 between source and destination worlds (add hex locations to the trade data to have this calculated)."
+	}
+	return fmt.Sprintf("The DM includes DM %d for the distance of %d parsecs between source and destination worlds.", parsecDM(parsecs), parsecs)
+}
+
 func LoadStandardTradeFacts(ctx *util.TASContext) (*model.TradeFacts, error) {
 	log := ctx.Logger()
 
@@ -405,8 +614,14 @@ func LoadStandardTradeFacts(ctx *util.TASContext) (*model.TradeFacts, error) {
 		return nil, errors.New(h.UnableToContinueBecauseOfErrors)
 	}
 
+	//trade codes are recognised from the trade code catalogue
+	tradeCodes, err := world.LoadTradeCodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	//parse the trade data and do further, detailed validation
-	hasErrors, errs := tradeFacts.Parse()
+	hasErrors, errs := tradeFacts.Parse(tradeCodes)
 	if hasErrors { //Parse truly failed with real errors
 		log.Error().Msg("parse of world UWP data failed. The following lines should point you to the problem")
 		for _, e := range errs {
@@ -423,5 +638,12 @@ func LoadStandardTradeFacts(ctx *util.TASContext) (*model.TradeFacts, error) {
 		}
 	}
 	log.Info().Msg("parsing trade data files complete")
+
+	//the ship profile, if there is one, replaces the character data
+	tradeFacts.Ship, err = LoadShipProfile(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	return tradeFacts, nil
 }
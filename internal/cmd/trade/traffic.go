@@ -0,0 +1,239 @@
+package trade
+
+import (
+	"fmt"
+
+	h "tas/internal/cmd/helpers"
+	"tas/internal/model"
+	"tas/internal/util"
+)
+
+/*
+	By default the standard trade DMs are reported and the Passenger and Freight Traffic tables are rolled at the
+	table. If the Effect of the check to find passengers (Broker, Carouse or Streetwise) or freight (Broker or
+	Streetwise) is given, or the skill level so the check can be rolled, the traffic is resolved into the passengers
+	and lots available and what they pay (see pgs 239 - 240)
+*/
+
+const (
+	EffectFlagName        = "effect"
+	SkillFlagName         = "skill"
+	FreightEffectFlagName = "freight-effect"
+	FreightSkillFlagName  = "freight-skill"
+	CargoFlagName         = "cargo"
+
+	trafficCheckTarget = 8
+	maxFareParsecs     = 6
+)
+
+// Passenger and Freight Traffic table (pgs 239 - 240): the number of dice of passengers for traffic values up to each max
+var trafficDiceByValue = []struct{ max, dice int }{
+	{1, 0}, {3, 1}, {6, 2}, {10, 3}, {13, 4}, {15, 5}, {16, 6}, {17, 7}, {18, 8}, {19, 9},
+}
+
+const trafficDiceBeyondTable = 10
+
+// fares (pg 239) by passage type for a jump of 1 to 6 parsecs
+var passageFareByParsecs = map[string][]int{
+	"high":   {9000, 14000, 21000, 34000, 60000, 210000},
+	"middle": {6500, 10000, 14000, 23000, 40000, 130000},
+	"basic":  {2000, 3000, 5000, 8000, 14000, 55000},
+	"low":    {700, 1300, 2200, 3900, 7200, 27000},
+}
+
+// freight price per ton (pg 240) for a jump of 1 to 6 parsecs
+var freightPriceByParsecs = []int{1000, 1600, 2600, 4400, 8500, 32000}
+
+// the dice rolled for the tons in one lot (pg 240), and what each die is multiplied by
+var freightLotSize = map[string]struct{ dice, multiplier int }{
+	"major":      {1, 10},
+	"minor":      {1, 5},
+	"incidental": {1, 1},
+}
+
+const freightLatePenalty = "If the freight is delivered late, payment is reduced by (1D+4) x 10%."
+
+// trafficCheck works out the Effect of the check made to find passengers or freight, either as given or by
+// rolling 2D + skill against 8. It is false if neither was given, in which case the traffic is not resolved
+func trafficCheck(ctx *util.TASContext, effectFlagName string, skillFlagName string) (int, bool, error) {
+
+	flags := ctx.Config().Flags
+	effectGiven := flags.Changed(effectFlagName)
+	skillGiven := flags.Changed(skillFlagName)
+
+	switch {
+	case effectGiven && skillGiven:
+		return 0, false, fmt.Errorf("give either the Effect of the check (--%s) or the skill level to roll it (--%s), not both", effectFlagName, skillFlagName)
+	case effectGiven:
+		effect, _ := flags.GetInt(effectFlagName)
+		return effect, true, nil
+	case skillGiven:
+		skill, _ := flags.GetInt(skillFlagName)
+		dice := util.NewDice(util.DeriveSeed(ctx.Dice().Seed(), skillFlagName))
+		roll := dice.Sum(2)
+		ctx.Logger().Info().Int("roll", roll).Int("skill", skill).Str("check", skillFlagName).Msg("rolled traffic check")
+		return roll + skill - trafficCheckTarget, true, nil
+	}
+	return 0, false, nil
+}
+
+// trafficDice is the number of dice rolled on the traffic table for a traffic value
+func trafficDice(value int) int {
+	for _, t := range trafficDiceByValue {
+		if value <= t.max {
+			return t.dice
+		}
+	}
+	return trafficDiceBeyondTable
+}
+
+// tripFare is the fare for a trip of the given parsecs. A trip longer than the ship can jump is made of several
+// jumps, each paid for on its own. An unknown distance is charged as a single parsec
+func tripFare(fares []int, parsecs int, jumpRating int) int {
+	if parsecs < 1 {
+		parsecs = 1
+	}
+	leg := h.MinInt(h.MaxInt(jumpRating, 1), maxFareParsecs)
+
+	fare := 0
+	for parsecs > leg {
+		fare += fares[leg-1]
+		parsecs -= leg
+	}
+	return fare + fares[parsecs-1]
+}
+
+// ResolvePassengers rolls the Passenger Traffic table for each passage type, using the Effect of the check, and
+// works out the fares for the trip. If the ship's berths are known, only the passengers that fit are carried.
+// The rolls use their own dice so the rest of the trade is unchanged
+func ResolvePassengers(ctx *util.TASContext, trade *model.StandardTradeModifiers, effect int, jumpRating int, ship *model.ShipProfile) {
+
+	log := ctx.Logger()
+	log.Info().Msg("Resolving passenger traffic...")
+
+	dice := util.NewDice(util.DeriveSeed(ctx.Dice().Seed(), "passengers"))
+	summary := trade.PassengerTrade
+	summary.CheckEffect = effect
+	summary.Traffic = make([]*model.PassengerTraffic, 0, len(summary.PassengerDMs))
+	summary.TotalIncome = 0
+
+	wanting := make(map[string]int)
+	for _, p := range summary.PassengerDMs {
+		t := &model.PassengerTraffic{PassageType: p.PassageType}
+		t.TrafficValue = dice.Sum(2) + p.DM + effect
+		t.Passengers = dice.Sum(trafficDice(t.TrafficValue))
+		t.Fare = tripFare(passageFareByParsecs[p.PassageType], trade.Parsecs, jumpRating)
+		summary.Traffic = append(summary.Traffic, t)
+		wanting[t.PassageType] = t.Passengers
+	}
+
+	//without the ship's berths, every passenger is assumed to fit
+	carried := wanting
+	if ship.IsFullProfile() {
+		carried = ship.PassengerCapacity(wanting)
+	}
+	for _, t := range summary.Traffic {
+		t.Carried = carried[t.PassageType]
+		t.Income = t.Carried * t.Fare
+		summary.TotalIncome += t.Income
+	}
+
+	log.Info().Int("income", summary.TotalIncome).Msg("Passenger traffic resolved")
+}
+
+// cargoSpace is the cargo space free for freight on this trip: the --cargo flag if given, or the ship's cargo tons.
+// It is not known for character data that gives no cargo tons; otherwise 0 is a full hold
+func cargoSpace(ctx *util.TASContext, ship *model.ShipProfile) (int, bool, error) {
+	if !ctx.Config().Flags.Changed(CargoFlagName) {
+		return ship.CargoTons, ship.IsFullProfile() || ship.CargoTons > 0, nil
+	}
+	cargoTons, _ := ctx.Config().Flags.GetInt(CargoFlagName)
+	if cargoTons < 0 {
+		return 0, false, fmt.Errorf("the cargo space (--%s) can't be negative: %d", CargoFlagName, cargoTons)
+	}
+	return cargoTons, true, nil
+}
+
+// ResolveFreight rolls the Freight Traffic table for each lot type, using the Effect of the check, and rolls the
+// tons in each lot. If the cargo space is known, the lots that best fill it are picked as the manifest. The rolls
+// use their own dice so the rest of the trade is unchanged
+func ResolveFreight(ctx *util.TASContext, trade *model.StandardTradeModifiers, effect int, jumpRating int, cargoTons int, cargoKnown bool) {
+
+	log := ctx.Logger()
+	log.Info().Msg("Resolving freight traffic...")
+
+	dice := util.NewDice(util.DeriveSeed(ctx.Dice().Seed(), "freight"))
+	summary := trade.FreightTrade
+	summary.CheckEffect = effect
+	summary.Traffic = make([]*model.FreightTraffic, 0, len(summary.FreightDMs))
+	summary.Lots = make([]*model.FreightLot, 0)
+	summary.PricePerTon = tripFare(freightPriceByParsecs, trade.Parsecs, jumpRating)
+	summary.CargoTons = cargoTons
+	summary.CargoKnown = cargoKnown
+	summary.LatePenalty = freightLatePenalty
+
+	for _, f := range summary.FreightDMs {
+		t := &model.FreightTraffic{LotType: f.LotType}
+		t.TrafficValue = dice.Sum(2) + f.DM + effect
+		t.Lots = dice.Sum(trafficDice(t.TrafficValue))
+		summary.Traffic = append(summary.Traffic, t)
+
+		size := freightLotSize[f.LotType]
+		for i := 0; i < t.Lots; i++ {
+			lot := &model.FreightLot{LotType: f.LotType, Tons: dice.Sum(size.dice) * size.multiplier}
+			lot.Income = lot.Tons * summary.PricePerTon
+			summary.Lots = append(summary.Lots, lot)
+		}
+	}
+
+	pickFreightLots(summary.Lots, cargoTons)
+
+	summary.TonsCarried = 0
+	summary.TotalIncome = 0
+	for _, lot := range summary.Lots {
+		if lot.Carried {
+			summary.TonsCarried += lot.Tons
+			summary.TotalIncome += lot.Income
+		}
+	}
+
+	log.Info().Int("tons", summary.TonsCarried).Int("income", summary.TotalIncome).Msg("Freight traffic resolved")
+}
+
+// pickFreightLots marks the lots that fill as much of the cargo space as possible. Every ton pays the same, so
+// the most tons is also the most income. Lots are all-or-nothing, so this is worked out as a knapsack over tons
+func pickFreightLots(lots []*model.FreightLot, cargoTons int) {
+	if cargoTons <= 0 || len(lots) == 0 {
+		return
+	}
+
+	//space beyond the tons of all the lots can't be used, and would only make the table bigger
+	totalTons := 0
+	for _, lot := range lots {
+		totalTons += lot.Tons
+	}
+	cargoTons = h.MinInt(cargoTons, totalTons)
+
+	//best[i][t] is the most tons that can be carried in t tons of space from the first i lots
+	best := make([][]int, len(lots)+1)
+	for i := range best {
+		best[i] = make([]int, cargoTons+1)
+	}
+	for i, lot := range lots {
+		for t := 0; t <= cargoTons; t++ {
+			best[i+1][t] = best[i][t]
+			if lot.Tons <= t {
+				best[i+1][t] = h.MaxInt(best[i+1][t], best[i][t-lot.Tons]+lot.Tons)
+			}
+		}
+	}
+
+	//walk back through the table to find which lots made up the best fill
+	t := cargoTons
+	for i := len(lots); i > 0; i-- {
+		if best[i][t] != best[i-1][t] {
+			lots[i-1].Carried = true
+			t -= lots[i-1].Tons
+		}
+	}
+}
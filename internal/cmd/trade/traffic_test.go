@@ -0,0 +1,49 @@
+package trade
+
+import (
+	"testing"
+
+	"tas/internal/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPickFreightLots(t *testing.T) {
+
+	lotsOf := func(tons ...int) []*model.FreightLot {
+		lots := make([]*model.FreightLot, 0, len(tons))
+		for _, t := range tons {
+			lots = append(lots, &model.FreightLot{Tons: t})
+		}
+		return lots
+	}
+	carried := func(lots []*model.FreightLot) int {
+		sum := 0
+		for _, l := range lots {
+			if l.Carried {
+				sum += l.Tons
+			}
+		}
+		return sum
+	}
+
+	//taking the biggest lot first would leave 20 tons empty, the two 30 ton lots fill the hold
+	lots := lotsOf(40, 30, 30)
+	pickFreightLots(lots, 60)
+	assert.Equal(t, 60, carried(lots))
+
+	//nothing fits
+	lots = lotsOf(40, 30)
+	pickFreightLots(lots, 20)
+	assert.Equal(t, 0, carried(lots))
+
+	//no cargo space
+	lots = lotsOf(10)
+	pickFreightLots(lots, 0)
+	assert.Equal(t, 0, carried(lots))
+
+	//far more space than freight carries every lot without building a huge table
+	lots = lotsOf(40, 30, 20)
+	pickFreightLots(lots, 1000000000)
+	assert.Equal(t, 90, carried(lots))
+}
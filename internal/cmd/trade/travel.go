@@ -0,0 +1,115 @@
+package trade
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	h "tas/internal/cmd/helpers"
+	"tas/internal/cmd/world"
+	"tas/internal/hexgrid"
+	"tas/internal/model"
+	"tas/internal/util"
+)
+
+/*
+	Travel time for a trip. The ship moves out from the source world to its 100 diameter limit under its m-drive,
+	makes each jump (about a week: 148 + 6D hours) and moves in from the destination's limit. A trip of more than
+	one jump only counts the jumps between the two ends; time spent at worlds along the way is not known.
+	Finding a supplier or broker for speculative trade takes 1D days (pg 241)
+*/
+
+const (
+	supplierSearchDice = 1
+)
+
+// TravelTime rolls how long a trip of the given number of jumps between the two worlds takes. A world with no
+// trade data (nil) adds no time moving to or from its jump limit
+func TravelTime(ctx *util.TASContext, fromData *model.WorldTradeInfo, toData *model.WorldTradeInfo, jumps int, ship *model.ShipProfile, sizes model.WorldSizeMap) *model.TravelTime {
+	dice := util.NewDice(util.DeriveSeed(ctx.Dice().Seed(), "travel"))
+	thrust := ship.Thrust()
+
+	jumpHours := make([]int, jumps)
+	for i := range jumpHours {
+		jumpHours[i] = model.JumpBaseHours + dice.Sum(model.JumpVarianceDice)
+	}
+
+	return model.NewTravelTime(thrust, jumpLimitHours(fromData, sizes, thrust), jumpHours, jumpLimitHours(toData, sizes, thrust))
+}
+
+// TripTravelTime rolls the travel time between two worlds in the trade data file. With no trade data file the
+// worlds are unknown, so the trip is taken to be a single jump
+func TripTravelTime(ctx *util.TASContext, from string, to string) (*model.TravelTime, error) {
+	log := ctx.Logger()
+
+	tradeDataFilename, err := ctx.Config().Flags.GetString(TradeFileFlagName)
+	if err != nil {
+		tradeDataFilename = defaultTradeDataFilename
+	}
+	if _, err := os.Stat(filepath.Join("data-local", tradeDataFilename)); err != nil {
+		log.Warn().Str("filename", tradeDataFilename).Msg("no trade data file, so the travel time is a single jump")
+		return TravelTime(ctx, nil, nil, 1, &model.ShipProfile{}, nil), nil
+	}
+
+	tradeFacts, err := LoadStandardTradeFacts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	fromData, ok := tradeFacts.DataForWorldName(from)
+	if !ok {
+		return nil, fmt.Errorf("the origin/from world: %s is not defined in the trade data file", from)
+	}
+	toData, ok := tradeFacts.DataForWorldName(to)
+	if !ok {
+		return nil, fmt.Errorf("the destination/to world: %s is not defined in the trade data file", to)
+	}
+	sizes, err := world.LoadWorldSizes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	//the number of jumps is only known if both worlds have a hex location
+	jumps := 1
+	if fromData.Hex != nil && toData.Hex != nil {
+		jumpRating := tradeFacts.JumpRating()
+		if jumpRating < 1 {
+			return nil, model.ErrCannotJump
+		}
+		jumps = h.MaxInt((hexgrid.Distance(*fromData.Hex, *toData.Hex)+jumpRating-1)/jumpRating, 1)
+	} else {
+		log.Warn().Msg("the worlds have no hex locations, so the trip is taken to be a single jump")
+	}
+
+	return TravelTime(ctx, fromData, toData, jumps, tradeFacts.ShipProfile(), sizes), nil
+}
+
+// SupplierSearchDays rolls the days taken to find a supplier or broker
+func SupplierSearchDays(ctx *util.TASContext) int {
+	dice := util.NewDice(util.DeriveSeed(ctx.Dice().Seed(), "supplier-search"))
+	return dice.Sum(supplierSearchDice)
+}
+
+// ledgerDate is the date in the campaign ledger, if there is one with a valid date
+func ledgerDate(ctx *util.TASContext) (model.ImperialDate, bool) {
+	l, err := h.LoadLedger(ctx)
+	if err != nil {
+		return model.ImperialDate{}, false
+	}
+	date, err := model.ParseImperialDate(l.Date)
+	if err != nil {
+		ctx.Logger().Warn().Err(err).Msg("the ledger date cannot be used")
+		return model.ImperialDate{}, false
+	}
+	return date, true
+}
+
+func jumpLimitHours(data *model.WorldTradeInfo, sizes model.WorldSizeMap, thrust int) int {
+	if data == nil {
+		return 0
+	}
+	size, ok := sizes[data.Size]
+	if !ok {
+		return 0
+	}
+	return model.JumpLimitHours(size.DiameterKm(), thrust)
+}
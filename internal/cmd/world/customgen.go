@@ -1,6 +1,8 @@
 package world
 
 import (
+	"fmt"
+
 	h "tas/internal/cmd/helpers"
 	"tas/internal/model"
 	"tas/internal/util"
@@ -24,6 +26,7 @@ func customHydrographics_FixAirlessWaterWorlds(ctx *util.TASContext, def *model.
 		def.Hydrographics = 0
 	} else {
 
+		ctx.Audit().Label("hydrographics 2D-7+atmosphere (custom)")
 		atmoMod := 0
 		atmoMod = h.AdjustDM(ctx, atmoMod, -4, def.Atmosphere, h.IS, 1, 10, 11, 12, 13, 14, 15)
 
@@ -35,6 +38,7 @@ func customHydrographics_FixAirlessWaterWorlds(ctx *util.TASContext, def *model.
 
 		hydro := dice.Sum(2, -7, def.Atmosphere, atmoMod, tempMod)
 		hydro = util.BoundTo(hydro, hydroMin, hydroMax)
+		ctx.Audit().Final(hydro)
 		def.Hydrographics = hydro
 	}
 	log.Debug().Str("custom", "customHydrographics_FixAirlessWaterWorlds").Int("hydro", def.Hydrographics).Send()
@@ -127,6 +131,8 @@ func customTechLevel_FixLowTechValues(ctx *util.TASContext, def *model.WorldDefi
 	//at this point we have a baseline established, add a bit of randomness
 	//allow tech to drift downward (indicating infrastrucural decay or remoteness)
 	//or increase a bit for whatever reason
+	ctx.Audit().Label("tech level drift 1D (custom)")
+	ctx.Audit().Reason(fmt.Sprintf("baseline tech level for this world's conditions is %d", techLevel))
 	adj := dice.Roll()
 	switch adj {
 	case 1:
@@ -142,6 +148,7 @@ func customTechLevel_FixLowTechValues(ctx *util.TASContext, def *model.WorldDefi
 		techLevel = 0
 	}
 
+	ctx.Audit().Final(techLevel)
 	def.TechLevel = techLevel
 	log.Debug().Str("custom", "customTechLevel_FixLowTechValues").Int("techLevel", def.TechLevel).Send()
 
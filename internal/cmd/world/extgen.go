@@ -0,0 +1,137 @@
+package world
+
+import (
+	h "tas/internal/cmd/helpers"
+	"tas/internal/model"
+	"tas/internal/util"
+)
+
+/*
+	The T5 extensions are not part of the core rules, but are used throughout published sector data so
+	generated worlds can sit alongside published ones. These follow the T5 rules for each extension
+*/
+
+// ---------------------------------------
+// Importance (Ix) is not rolled, it is a sum of DMs
+// ---------------------------------------
+func generateImportance(ctx *util.TASContext, def *model.WorldDefinition) {
+
+	log := ctx.Logger()
+
+	ctx.Audit().Label("importance")
+	ix := 0
+
+	//starport class A or B (9+) is important, D or worse (6-) is not
+	ix = h.AdjustDM(ctx, ix, 1, def.Starport.Value, h.GE, 9)
+	ix = h.AdjustDM(ctx, ix, -1, def.Starport.Value, h.LE, 6)
+
+	//tech level
+	ix = h.AdjustDM(ctx, ix, 1, def.TechLevel, h.GE, 10)
+	ix = h.AdjustDM(ctx, ix, 1, def.TechLevel, h.GE, 16)
+	ix = h.AdjustDM(ctx, ix, -1, def.TechLevel, h.LE, 8)
+
+	//population
+	ix = h.AdjustDM(ctx, ix, -1, def.Population, h.LE, 6)
+
+	//each of these trade codes add 1
+	for _, tc := range def.TradeCodes {
+		switch tc {
+		case "Ag", "Hi", "In", "Ri":
+			ix++
+			ctx.Audit().Reason("DM+1 (trade code " + tc + ")")
+		}
+	}
+
+	//a naval base and scout base together add 1, as does a way station
+	hasBase := func(base string) bool {
+		for _, b := range def.Bases {
+			if b == base {
+				return true
+			}
+		}
+		return false
+	}
+	if hasBase("naval") && hasBase("scout") {
+		ix++
+		ctx.Audit().Reason("DM+1 (naval and scout bases)")
+	}
+	if hasBase("way station") {
+		ix++
+		ctx.Audit().Reason("DM+1 (way station)")
+	}
+	ctx.Audit().Value(ix)
+
+	def.Extensions = &model.WorldExtensions{Importance: ix}
+	log.Debug().Int("importance", ix).Send()
+}
+
+// ---------------------------------------
+// Economic extension (Ex)
+// ---------------------------------------
+func generateEconomics(ctx *util.TASContext, def *model.WorldDefinition) {
+
+	log := ctx.Logger()
+	dice := ctx.Dice()
+
+	ex := &model.WorldEconomics{}
+
+	ctx.Audit().Label("resources 2D")
+	ex.Resources = dice.Sum(2)
+
+	//an unpopulated world has resources, but nothing else
+	if def.Population > 0 {
+		ex.Labor = util.BoundTo(def.Population-1, 0, def.Population)
+
+		ix := def.Extensions.Importance
+		switch {
+		case def.Population <= 3:
+			ex.Infrastructure = ix
+		case def.Population <= 6:
+			ctx.Audit().Label("infrastructure 1D+importance")
+			ex.Infrastructure = dice.Roll(ix)
+		default:
+			ctx.Audit().Label("infrastructure 2D+importance")
+			ex.Infrastructure = dice.Sum(2, ix)
+		}
+		if ex.Infrastructure < 0 {
+			ex.Infrastructure = 0
+		}
+
+		ctx.Audit().Label("efficiency flux")
+		ex.Efficiency = dice.Flux()
+	}
+
+	def.Extensions.Economic = ex
+	log.Debug().Str("economic", def.Extensions.EconomicString()).Send()
+}
+
+// ---------------------------------------
+// Cultural extension (Cx)
+// ---------------------------------------
+func generateCulturalExtension(ctx *util.TASContext, def *model.WorldDefinition) {
+
+	log := ctx.Logger()
+	dice := ctx.Dice()
+
+	//an unpopulated world has no culture
+	cx := &model.WorldCulturalExtension{}
+	if def.Population > 0 {
+		ctx.Audit().Label("heterogeneity flux+population")
+		cx.Heterogeneity = atLeastOne(dice.Flux() + def.Population)
+		cx.Acceptance = atLeastOne(def.Population + def.Extensions.Importance)
+		ctx.Audit().Label("strangeness flux+5")
+		cx.Strangeness = atLeastOne(dice.Flux() + 5)
+		ctx.Audit().Label("symbols flux+tech level")
+		cx.Symbols = atLeastOne(dice.Flux() + def.TechLevel)
+	}
+
+	def.Extensions.Cultural = cx
+	log.Debug().Str("cultural", def.Extensions.CulturalString()).Send()
+}
+
+func atLeastOne(i int) int {
+	if i < 1 {
+		return 1
+	}
+	return i
+}
@@ -7,6 +7,7 @@ import (
 )
 
 const (
+	systemFunc        = "system"
 	sizeFunc          = "size"
 	atmosphereFunc    = "atmo"
 	temperatureFunc   = "temp"
@@ -22,6 +23,9 @@ const (
 	basesFunc         = "bases"
 	travelFunc        = "trav"
 	tradeFunc         = "trade"
+	importanceFunc    = "imp"
+	economicFunc      = "econ"
+	culturalExtFunc   = "cultx"
 )
 
 type generatorFunction func(ctx *util.TASContext, def *model.WorldDefinition)
@@ -32,11 +36,12 @@ type generatorScheme map[string]generatorFunction
 // the as-written rules are used, but these can be customized to have other generator
 // functions overwrite one or more of the standard functions with a (hopefully) better
 // function that generates better results
-func generatorSchemeForName(scheme h.SchemeType) generatorScheme {
+func generatorSchemeForName(scheme h.SchemeType, src *model.WorldSource) generatorScheme {
 
 	genSchema := make(generatorScheme)
 
 	//establish baseline generators - use the standard functions to do it by-the-book
+	genSchema[systemFunc] = generateSystem
 	genSchema[sizeFunc] = generateSize
 	genSchema[atmosphereFunc] = generateAtmosphere
 	genSchema[temperatureFunc] = generateTemperature
@@ -51,7 +56,10 @@ func generatorSchemeForName(scheme h.SchemeType) generatorScheme {
 	genSchema[highportFunc] = generateHighport
 	genSchema[basesFunc] = generateBases
 	genSchema[travelFunc] = generateTravelCode
-	genSchema[tradeFunc] = generateTradeCodes
+	genSchema[tradeFunc] = tradeCodeGenerator(src.WorldTradeCodes)
+	genSchema[importanceFunc] = generateImportance
+	genSchema[economicFunc] = generateEconomics
+	genSchema[culturalExtFunc] = generateCulturalExtension
 
 	//allow override baseline if desired
 	switch scheme {
@@ -1,6 +1,8 @@
 package world
 
 import (
+	"fmt"
+
 	h "tas/internal/cmd/helpers"
 	"tas/internal/model"
 	"tas/internal/util"
@@ -13,8 +15,10 @@ func generateSize(ctx *util.TASContext, def *model.WorldDefinition) {
 	log := ctx.Logger()
 	dice := ctx.Dice()
 
+	ctx.Audit().Label("size 2D-2")
 	size := dice.Sum(2, -2)
 	size = util.BoundTo(size, sizeMin, sizeMax)
+	ctx.Audit().Final(size)
 	def.Size = size
 	log.Debug().Int("size", def.Size).Send()
 }
@@ -28,8 +32,10 @@ func generateAtmosphere(ctx *util.TASContext, def *model.WorldDefinition) {
 
 	def.Atmosphere = 0
 	if def.Size > 1 {
+		ctx.Audit().Label("atmosphere 2D-7+size")
 		atmos := dice.Sum(2, -7, def.Size)
 		atmos = util.BoundTo(atmos, atmoMin, atmoMax)
+		ctx.Audit().Final(atmos)
 		def.Atmosphere = atmos
 		log.Debug().Int("atmos", def.Atmosphere).Send()
 	}
@@ -48,35 +54,43 @@ func generateTemperature(ctx *util.TASContext, def *model.WorldDefinition) {
 		def.Temperature = specialTempCodeForNoAtmo
 		def.HabitabilityZone = "standard"
 	} else {
-		atmoMod := 0
-		atmoMod = h.AdjustDM(ctx, atmoMod, -2, def.Atmosphere, h.INR, 2, 3)
-		atmoMod = h.AdjustDM(ctx, atmoMod, -1, def.Atmosphere, h.IS, 4, 5, 14)
-		atmoMod = h.AdjustDM(ctx, atmoMod, 1, def.Atmosphere, h.INR, 8, 9)
-		atmoMod = h.AdjustDM(ctx, atmoMod, 6, def.Atmosphere, h.INR, 11, 12)
-		atmoMod = h.AdjustDM(ctx, atmoMod, 2, def.Atmosphere, h.IS, 10, 13, 15)
-
-		//this is optional, but I am adding a random location within the "habital zone"
-		//in a star system. This is optional per pg 251
+		//the mainworld's orbit relative to the habitable zone of its star decides how hot it is. This
+		//is optional per pg 251. A world without a star system (e.g. from a UWP) is in the standard zone
 		zoneMod := 0
 		habZone := "standard"
-		zone := dice.Sum(2)
-		switch zone {
-		case 2:
+		offset := 0
+		if def.System != nil {
+			offset = def.System.HabitableZoneOffset()
+		}
+		switch {
+		case offset >= 2:
 			zoneMod = -4
 			habZone = "extreme cold"
-		case 3:
+		case offset == 1:
 			zoneMod = -2
 			habZone = "cold"
-		case 11:
+		case offset == -1:
 			zoneMod = 2
 			habZone = "hot"
-		case 12:
+		case offset <= -2:
 			zoneMod = 4
 			habZone = "extreme hot"
 		}
 
+		ctx.Audit().Label("temperature 2D")
+		atmoMod := 0
+		atmoMod = h.AdjustDM(ctx, atmoMod, -2, def.Atmosphere, h.INR, 2, 3)
+		atmoMod = h.AdjustDM(ctx, atmoMod, -1, def.Atmosphere, h.IS, 4, 5, 14)
+		atmoMod = h.AdjustDM(ctx, atmoMod, 1, def.Atmosphere, h.INR, 8, 9)
+		atmoMod = h.AdjustDM(ctx, atmoMod, 6, def.Atmosphere, h.INR, 11, 12)
+		atmoMod = h.AdjustDM(ctx, atmoMod, 2, def.Atmosphere, h.IS, 10, 13, 15)
+		if zoneMod != 0 {
+			ctx.Audit().Reason(fmt.Sprintf("DM%+d as world is in the %s part of the habitability zone", zoneMod, habZone))
+		}
+
 		temp := dice.Sum(2, atmoMod, zoneMod)
 		temp = util.BoundTo(temp, tempMin, tempMax)
+		ctx.Audit().Final(temp)
 		def.Temperature = temp
 		def.HabitabilityZone = habZone
 
@@ -96,6 +110,7 @@ func generateHydrographics(ctx *util.TASContext, def *model.WorldDefinition) {
 		def.Hydrographics = 0
 	} else {
 
+		ctx.Audit().Label("hydrographics 2D-7+atmosphere")
 		atmoMod := 0
 		atmoMod = h.AdjustDM(ctx, atmoMod, -4, def.Atmosphere, h.IS, 0, 1, 10, 11, 12, 13, 14, 15)
 
@@ -107,6 +122,7 @@ func generateHydrographics(ctx *util.TASContext, def *model.WorldDefinition) {
 
 		hydro := dice.Sum(2, -7, def.Atmosphere, atmoMod, tempMod)
 		hydro = util.BoundTo(hydro, hydroMin, hydroMax)
+		ctx.Audit().Final(hydro)
 		def.Hydrographics = hydro
 	}
 	log.Debug().Int("hydro", def.Hydrographics).Send()
@@ -120,8 +136,10 @@ func generatePopulation(ctx *util.TASContext, def *model.WorldDefinition) {
 	log := ctx.Logger()
 	dice := ctx.Dice()
 
+	ctx.Audit().Label("population 2D-2")
 	pop := dice.Sum(2, -2)
 	pop = util.BoundTo(pop, popMin, popMax)
+	ctx.Audit().Final(pop)
 	def.Population = pop
 	log.Debug().Int("pop", def.Population).Send()
 }
@@ -137,8 +155,10 @@ func generateGovernment(ctx *util.TASContext, def *model.WorldDefinition) {
 	if def.Population == 0 {
 		def.Government = 0
 	} else {
+		ctx.Audit().Label("government 2D-7+population")
 		gov := dice.Sum(2, -7, def.Population)
 		gov = util.BoundTo(gov, govMin, govMax)
+		ctx.Audit().Final(gov)
 		def.Government = gov
 	}
 	log.Debug().Int("gov", def.Government).Send()
@@ -154,6 +174,7 @@ func generateFactions(ctx *util.TASContext, def *model.WorldDefinition) {
 
 	var factionsList []*model.WorldFaction
 	if def.Population > 0 {
+		ctx.Audit().Label("number of factions D3")
 		fmod := 0
 		fmod = h.AdjustDM(ctx, fmod, 1, def.Government, h.IS, 0, 7)
 		fmod = h.AdjustDM(ctx, fmod, -1, def.Government, h.GE, 10)
@@ -161,8 +182,11 @@ func generateFactions(ctx *util.TASContext, def *model.WorldDefinition) {
 		numberOfFactions := dice.D3(fmod)
 		factionsList = make([]*model.WorldFaction, 0, numberOfFactions)
 		for i := 0; i < numberOfFactions; i++ {
+			ctx.Audit().Label(fmt.Sprintf("faction %d government 2D-7+population", i+1))
 			gov := dice.Sum(2, -7, def.Population)
 			gov = util.BoundTo(gov, govMin, govMax)
+			ctx.Audit().Final(gov)
+			ctx.Audit().Label(fmt.Sprintf("faction %d relative strength 2D", i+1))
 			strength := dice.Sum(2)
 			f := &model.WorldFaction{
 				GovernmentStyle:  gov,
@@ -188,6 +212,7 @@ func generateCulture(ctx *util.TASContext, def *model.WorldDefinition) {
 
 	def.Culture = specialCultureCodeForNoPop
 	if def.Population > 0 {
+		ctx.Audit().Label("culture D66")
 		def.Culture = dice.D66()
 	}
 	log.Debug().Int("culture", def.Culture).Send()
@@ -204,8 +229,10 @@ func generateLawLevel(ctx *util.TASContext, def *model.WorldDefinition) {
 	if def.Population == 0 {
 		def.LawLevel = 0
 	} else {
+		ctx.Audit().Label("law level 2D-7+government")
 		law := dice.Sum(2, -7, def.Government)
 		law = util.BoundTo(law, lawMin, lawMax)
+		ctx.Audit().Final(law)
 		def.LawLevel = law
 	}
 	log.Debug().Int("law level", def.LawLevel).Send()
@@ -219,6 +246,7 @@ func generateStarport(ctx *util.TASContext, def *model.WorldDefinition) {
 	log := ctx.Logger()
 	dice := ctx.Dice()
 
+	ctx.Audit().Label("starport 2D")
 	popMod := 0
 	popMod = h.AdjustDM(ctx, popMod, 1, def.Population, h.INR, 8, 9)
 	popMod = h.AdjustDM(ctx, popMod, 2, def.Population, h.GE, 10)
@@ -227,9 +255,11 @@ func generateStarport(ctx *util.TASContext, def *model.WorldDefinition) {
 
 	star := dice.Sum(2, popMod)
 	star = util.BoundTo(star, starMin, starMax)
+	ctx.Audit().Final(star)
 	starport := &model.WorldStarportInfo{}
 	starport.Value = star
 
+	ctx.Audit().Label("starport berthing cost 1D")
 	switch starport.Value {
 	case 2, 3, 4:
 		starport.HasHighport = false
@@ -261,6 +291,8 @@ func generateTechLevel(ctx *util.TASContext, def *model.WorldDefinition) {
 		def.TechLevel = 0
 	} else {
 
+		ctx.Audit().Label("tech level 1D")
+
 		//starport modifier
 		starMod := 0
 		starMod = h.AdjustDM(ctx, starMod, -4, def.Starport.Value, h.EQ, 2)
@@ -320,6 +352,7 @@ func generateTechLevel(ctx *util.TASContext, def *model.WorldDefinition) {
 			tech = util.BoundTo(tech, 10, techMax)
 		}
 
+		ctx.Audit().Final(tech)
 		def.TechLevel = tech
 	}
 	log.Debug().Int("tech level", def.TechLevel).Send()
@@ -351,6 +384,7 @@ func generateHighport(ctx *util.TASContext, def *model.WorldDefinition) {
 		return
 	}
 
+	ctx.Audit().Label("highport 2D")
 	hpTechMod := 0
 	hpTechMod = h.AdjustDM(ctx, hpTechMod, 1, def.TechLevel, h.INR, 9, 11)
 	hpTechMod = h.AdjustDM(ctx, hpTechMod, 2, def.TechLevel, h.GE, 12)
@@ -371,46 +405,61 @@ func generateBases(ctx *util.TASContext, def *model.WorldDefinition) {
 	log := ctx.Logger()
 	dice := ctx.Dice()
 
-	corsairLawMod := 0
-	corsairLawMod = h.AdjustDM(ctx, corsairLawMod, 2, def.LawLevel, h.EQ, 0)
-	corsairLawMod = h.AdjustDM(ctx, corsairLawMod, -2, def.LawLevel, h.GE, 2)
+	//corsair bases are more likely where there is little law
+	corsairLawMod := func() int {
+		mod := 0
+		mod = h.AdjustDM(ctx, mod, 2, def.LawLevel, h.EQ, 0)
+		mod = h.AdjustDM(ctx, mod, -2, def.LawLevel, h.GE, 2)
+		return mod
+	}
 
 	switch def.Starport.Value {
 	case 2, 3, 4:
-		if dice.Sum(2, corsairLawMod) >= 10 {
+		ctx.Audit().Label("corsair base 2D")
+		if dice.Sum(2, corsairLawMod()) >= 10 {
 			baseList = append(baseList, "corsair")
 		}
 	case 5, 6:
-		if dice.Sum(2, corsairLawMod) >= 12 {
+		ctx.Audit().Label("corsair base 2D")
+		if dice.Sum(2, corsairLawMod()) >= 12 {
 			baseList = append(baseList, "corsair")
 		}
+		ctx.Audit().Label("scout base 2D")
 		if dice.Sum(2) >= 8 {
 			baseList = append(baseList, "scout")
 		}
 	case 7, 8:
+		ctx.Audit().Label("military base 2D")
 		if dice.Sum(2) >= 10 {
 			baseList = append(baseList, "military")
 		}
+		ctx.Audit().Label("scout base 2D")
 		if dice.Sum(2) >= 9 {
 			baseList = append(baseList, "scout")
 		}
 	case 9, 10:
+		ctx.Audit().Label("military base 2D")
 		if dice.Sum(2) >= 8 {
 			baseList = append(baseList, "military")
 		}
+		ctx.Audit().Label("naval base 2D")
 		if dice.Sum(2) >= 8 {
 			baseList = append(baseList, "naval")
 		}
+		ctx.Audit().Label("scout base 2D")
 		if dice.Sum(2) >= 9 {
 			baseList = append(baseList, "scout")
 		}
 	case 11:
+		ctx.Audit().Label("military base 2D")
 		if dice.Sum(2) >= 8 {
 			baseList = append(baseList, "military")
 		}
+		ctx.Audit().Label("naval base 2D")
 		if dice.Sum(2) >= 8 {
 			baseList = append(baseList, "naval")
 		}
+		ctx.Audit().Label("scout base 2D")
 		if dice.Sum(2) >= 10 {
 			baseList = append(baseList, "scout")
 		}
@@ -448,112 +497,15 @@ func generateTravelCode(ctx *util.TASContext, def *model.WorldDefinition) {
 // ---------------------------------------
 // Trade Codes pg 260
 // ---------------------------------------
-func generateTradeCodes(ctx *util.TASContext, def *model.WorldDefinition) {
-
-	log := ctx.Logger()
 
-	codes := make([]string, 0)
+// tradeCodeGenerator gives a world every trade code from the catalogue (data/world-trade-codes.json) that it
+// meets the criteria for
+func tradeCodeGenerator(catalogue model.WorldTradeCodeMap) generatorFunction {
+	return func(ctx *util.TASContext, def *model.WorldDefinition) {
 
-	//agricultural
-	if def.Atmosphere >= 4 && def.Atmosphere <= 9 && def.Hydrographics >= 4 && def.Hydrographics <= 8 && def.Population >= 5 && def.Population <= 7 {
-		codes = append(codes, "agricultural")
-	}
-
-	//asteroid
-	if def.Size == 0 && def.Atmosphere == 0 && def.Hydrographics == 0 {
-		codes = append(codes, "asteroid")
-	}
-
-	//barren
-	if def.Population == 0 && def.Government == 0 && def.LawLevel == 0 {
-		codes = append(codes, "barren")
-	}
-
-	//desert
-	if def.Atmosphere >= 2 && def.Atmosphere <= 9 && def.Hydrographics == 0 {
-		codes = append(codes, "desert")
-	}
+		log := ctx.Logger()
 
-	//fluid oceans
-	if def.Atmosphere >= 10 && def.Hydrographics >= 1 {
-		codes = append(codes, "fluid oceans")
+		def.TradeCodes = catalogue.CodesFor(def)
+		log.Debug().Int("number of trade codes", len(def.TradeCodes)).Send()
 	}
-
-	//garden
-	if def.Size >= 6 && def.Size <= 8 && (def.Atmosphere == 5 || def.Atmosphere == 6 || def.Atmosphere == 8) && def.Hydrographics >= 5 && def.Hydrographics <= 7 {
-		codes = append(codes, "garden")
-	}
-
-	//high pop
-	if def.Population >= 9 {
-		codes = append(codes, "high population")
-	}
-
-	//high tech
-	if def.TechLevel >= 12 {
-		codes = append(codes, "high tech")
-	}
-
-	//ice capped
-	if def.Atmosphere <= 1 && def.Hydrographics >= 1 {
-		codes = append(codes, "ice-capped")
-	}
-
-	//industrial
-	indAtmo := false
-	switch def.Atmosphere {
-	case 0, 1, 2, 4, 7, 9, 10, 11, 12:
-		indAtmo = true
-	}
-	if indAtmo && def.Population >= 9 {
-		codes = append(codes, "industrial")
-	}
-
-	//low pop
-	if def.Population <= 3 {
-		codes = append(codes, "low population")
-	}
-
-	//low tech
-	if def.Population >= 1 && def.TechLevel <= 5 {
-		codes = append(codes, "low tech")
-	}
-
-	//non-agricultural
-	if def.Atmosphere <= 3 && def.Hydrographics <= 3 && def.Population >= 6 {
-		codes = append(codes, "non-agricultural")
-	}
-
-	//non-industrial
-	if def.Population >= 4 && def.Population <= 6 {
-		codes = append(codes, "non-industrial")
-	}
-
-	//poor
-	if def.Atmosphere >= 2 && def.Atmosphere <= 5 && def.Hydrographics <= 3 {
-		codes = append(codes, "poor")
-	}
-
-	//rich
-	if (def.Atmosphere == 6 || def.Atmosphere == 8) && def.Population >= 6 && def.Population <= 8 && def.Government >= 4 && def.Government <= 9 {
-		codes = append(codes, "rich")
-	}
-
-	//vaccum
-	if def.Atmosphere == 0 {
-		codes = append(codes, "vacuum")
-	}
-
-	//waterworld
-	watAtmo := false
-	switch def.Atmosphere {
-	case 3, 4, 5, 6, 7, 8, 9, 13:
-		watAtmo = true
-	}
-	if watAtmo && def.Hydrographics >= 10 {
-		codes = append(codes, "waterworld")
-	}
-
-	def.TradeCodes = codes
-	log.Debug().Int("number of trade codes", len(def.TradeCodes)).Send()
 }
@@ -0,0 +1,208 @@
+package world
+
+import (
+	"fmt"
+
+	"tas/internal/model"
+	"tas/internal/util"
+)
+
+/*
+	The star system is generated before the mainworld, as the mainworld's place in the system decides
+	where it sits relative to the habitable zone (see pg 251). Stars use the T5 flux tables; belts and
+	gas giants use the 2D presence rolls from pg 246
+*/
+
+const (
+	gasGiantThreshold      = 10 //gas giants are present on 2D less than this
+	planetoidBeltThreshold = 8  //planetoid belts are present on 2D of at least this
+	companionThreshold     = 3  //a companion star is present in each position on a flux of at least this
+)
+
+// spectral classes from hottest to coolest
+var spectralClasses = []string{"O", "B", "A", "F", "G", "K", "M"}
+
+// primary spectral class by flux (-5 to +5)
+var primaryClassByFlux = map[int]string{
+	-5: "B", -4: "A", -3: "F", -2: "F", -1: "G", 0: "G", 1: "K", 2: "K", 3: "M", 4: "M", 5: "M",
+}
+
+// habitable zone orbit for a main sequence (size V) star of each spectral class
+var habitableZoneOrbitByClass = map[string]int{
+	"O": 14, "B": 12, "A": 7, "F": 4, "G": 3, "K": 1, "M": 0,
+}
+
+var companionPositions = []string{"close", "near", "far"}
+
+// ---------------------------------------
+// Star system: stars, belts, gas giants and orbits
+// ---------------------------------------
+func generateSystem(ctx *util.TASContext, def *model.WorldDefinition) {
+
+	log := ctx.Logger()
+	dice := ctx.Dice()
+
+	sys := &model.StarSystem{
+		Companions: make([]*model.Star, 0),
+	}
+
+	//primary star
+	ctx.Audit().Label("primary spectral class flux")
+	class := primaryClassByFlux[dice.Flux()]
+	ctx.Audit().Label("primary size flux")
+	sys.Primary = &model.Star{
+		SpectralClass: class,
+		Size:          starSizeForFlux(class, dice.Flux()),
+	}
+	ctx.Audit().Label("primary decimal 1D10-1")
+	sys.Primary.Decimal = dice.Dx(10) - 1
+
+	//companions
+	for _, pos := range companionPositions {
+		ctx.Audit().Label(fmt.Sprintf("%s companion flux", pos))
+		if dice.Flux() >= companionThreshold {
+			sys.Companions = append(sys.Companions, generateCompanion(ctx, sys.Primary, pos))
+		}
+	}
+
+	//population multiplier is always rolled, but is only shown for populated worlds
+	ctx.Audit().Label("population multiplier 1D9")
+	sys.PopulationMultiplier = dice.Dx(9)
+
+	ctx.Audit().Label("planetoid belts 2D")
+	if dice.Sum(2) >= planetoidBeltThreshold {
+		ctx.Audit().Label("number of planetoid belts 1D")
+		sys.PlanetoidBelts = countForRoll(dice.Roll(), 1, 1, 1, 1, 2, 3)
+	}
+
+	ctx.Audit().Label("gas giants 2D")
+	if dice.Sum(2) < gasGiantThreshold {
+		ctx.Audit().Label("number of gas giants 1D")
+		sys.GasGiants = countForRoll(dice.Roll(), 1, 1, 2, 2, 3, 4)
+	}
+
+	//habitable zone is further out for hotter and larger stars
+	hz := habitableZoneOrbitByClass[class]
+	switch sys.Primary.Size {
+	case "II", "III":
+		hz += 2
+	case "IV":
+		hz++
+	case "VI":
+		hz--
+	}
+	if hz < 0 {
+		hz = 0
+	}
+	sys.HabitableZoneOrbit = hz
+
+	//placement of the mainworld matches the habitability zone roll on pg 251
+	ctx.Audit().Label("mainworld orbit 2D")
+	offset := 0
+	switch dice.Sum(2) {
+	case 2:
+		offset = 2
+	case 3:
+		offset = 1
+	case 11:
+		offset = -1
+	case 12:
+		offset = -2
+	}
+	//there is no orbit inside orbit 0, so a world around a dim star may sit further out than rolled
+	sys.MainworldOrbit = sys.HabitableZoneOrbit + offset
+	if sys.MainworldOrbit < 0 {
+		sys.MainworldOrbit = 0
+	}
+
+	ctx.Audit().Label("number of orbits 2D")
+	numOrbits := dice.Sum(2)
+	sys.Orbits = placeOrbits(sys, numOrbits)
+
+	def.System = sys
+	log.Debug().Str("stars", sys.Stars()).Int("hz-orbit", sys.HabitableZoneOrbit).Int("mainworld-orbit", sys.MainworldOrbit).Send()
+}
+
+func starSizeForFlux(class string, f int) string {
+	switch {
+	case f <= -5:
+		return "II"
+	case f == -4:
+		return "III"
+	case f == -3 && class != "M":
+		return "IV"
+	case f >= 4 && (class == "K" || class == "M"):
+		return "VI"
+	}
+	return "V"
+}
+
+// companions are main sequence stars no hotter than the primary, or white dwarfs
+func generateCompanion(ctx *util.TASContext, primary *model.Star, position string) *model.Star {
+	dice := ctx.Dice()
+
+	ctx.Audit().Label(fmt.Sprintf("%s companion type 1D", position))
+	if dice.Roll() == 1 {
+		return &model.Star{Size: model.StarSizeWhiteDwarf, Position: position}
+	}
+
+	idx := 0
+	for i, c := range spectralClasses {
+		if c == primary.SpectralClass {
+			idx = i
+		}
+	}
+	ctx.Audit().Label(fmt.Sprintf("%s companion class D3-1", position))
+	idx = util.BoundTo(idx+dice.D3(-1), 0, len(spectralClasses)-1)
+	ctx.Audit().Label(fmt.Sprintf("%s companion decimal 1D10-1", position))
+	return &model.Star{
+		SpectralClass: spectralClasses[idx],
+		Decimal:       dice.Dx(10) - 1,
+		Size:          "V",
+		Position:      position,
+	}
+}
+
+func countForRoll(roll int, counts ...int) int {
+	return counts[util.BoundTo(roll, 1, len(counts))-1]
+}
+
+// the mainworld takes its orbit first, then gas giants fill the outer zone and belts are placed
+// outward from the mainworld. More orbits are added if there isn't room for everything
+func placeOrbits(sys *model.StarSystem, numOrbits int) []*model.SystemOrbit {
+
+	needed := 1 + sys.GasGiants + sys.PlanetoidBelts
+	if numOrbits < needed {
+		numOrbits = needed
+	}
+	if numOrbits <= sys.MainworldOrbit {
+		numOrbits = sys.MainworldOrbit + 1
+	}
+
+	orbits := make([]*model.SystemOrbit, 0, numOrbits)
+	for i := 0; i < numOrbits; i++ {
+		zone := model.OrbitZoneHabitable
+		if i < sys.HabitableZoneOrbit {
+			zone = model.OrbitZoneInner
+		} else if i > sys.HabitableZoneOrbit {
+			zone = model.OrbitZoneOuter
+		}
+		orbits = append(orbits, &model.SystemOrbit{Number: i, Zone: zone, Contents: model.OrbitContentsEmpty})
+	}
+	orbits[sys.MainworldOrbit].Contents = model.OrbitContentsMainworld
+
+	//fill empty orbits starting from 'from', wrapping around to the innermost orbit
+	place := func(contents string, count int, from int) {
+		for i := 0; i < len(orbits) && count > 0; i++ {
+			o := orbits[(from+i)%len(orbits)]
+			if o.Contents == model.OrbitContentsEmpty {
+				o.Contents = contents
+				count--
+			}
+		}
+	}
+	place(model.OrbitContentsGasGiant, sys.GasGiants, sys.HabitableZoneOrbit+1)
+	place(model.OrbitContentsPlanetoidBelt, sys.PlanetoidBelts, sys.MainworldOrbit+1)
+
+	return orbits
+}
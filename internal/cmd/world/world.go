@@ -18,6 +18,8 @@ const (
 	//name of flag that holds worldgen info
 	WorldGenSchemeFlagName = "worldscheme"
 	LongformOutputFlagName = "long"
+	ExplainFlagName        = "explain"
+	ExtensionsFlagName     = "extensions"
 
 	maxNumberOfWorldsToGenerate = 1000
 
@@ -42,6 +44,7 @@ const (
 
 	//special one-off temp values to indicate special circumstances on a table
 	specialTempCodeForNoAtmo   = -1 //indicates that temperature is boiling/freezing at day/night
+	specialTempCodeForUnknown  = 0  //temperature is not part of the UWP, so a parsed world has no known temperature
 	specialCultureCodeForNoPop = 0  //there is no populatiobn, so there is no culture
 
 	//table min/max bounds
@@ -106,7 +109,7 @@ func worldCmd(cmd *cobra.Command, args []string) {
 	log := util.NewLogger(loglevel)
 	ctx := util.NewContext().
 		WithLogger(log).
-		WithDice().
+		WithDice(cfg.Seed()).
 		WithConfig(cfg)
 
 	//determine if we want standard (as written) worldgen or want to use the custom generator
@@ -136,17 +139,28 @@ func worldCmd(cmd *cobra.Command, args []string) {
 		}
 	}
 
+	explain, _ := cfg.Flags.GetBool(ExplainFlagName)
+
 	for i := 0; uint64(i) < numberOfWorldsToGenerate; i++ {
 
+		//record the rolls for each world separately if an explanation was requested
+		worldCtx := ctx
+		if explain {
+			worldCtx = ctx.Fork().WithAudit()
+		}
+
 		//generate the world
-		def := GenerateWorld(ctx, schemeType)
+		def := GenerateWorld(worldCtx, schemeType, src)
 
 		//summarize the world in a JSON-ready object
-		summary, err := GenerateWorldSummary(ctx, def, src)
+		summary, err := GenerateWorldSummary(worldCtx, def, src)
 		if err != nil {
 			log.Error().Err(err).Msg("unable to create world summary")
 			return
 		}
+		if explain {
+			summary.Audit = worldCtx.Audit().Entries
+		}
 
 		//add the long description to the summary
 		BuildLongDescription(ctx, summary)
@@ -157,15 +171,16 @@ func worldCmd(cmd *cobra.Command, args []string) {
 
 }
 
-func GenerateWorld(ctx *util.TASContext, schemeName h.SchemeType) *model.WorldDefinition {
+func GenerateWorld(ctx *util.TASContext, schemeName h.SchemeType, src *model.WorldSource) *model.WorldDefinition {
 	def := &model.WorldDefinition{}
 
 	log := ctx.Logger()
 
 	log.Info().Msg("generating world...")
 
-	genScheme := generatorSchemeForName(schemeName)
+	genScheme := generatorSchemeForName(schemeName, src)
 
+	genScheme[systemFunc](ctx, def)
 	genScheme[sizeFunc](ctx, def)
 	genScheme[atmosphereFunc](ctx, def)
 	genScheme[temperatureFunc](ctx, def)
@@ -181,6 +196,9 @@ func GenerateWorld(ctx *util.TASContext, schemeName h.SchemeType) *model.WorldDe
 	genScheme[basesFunc](ctx, def)
 	genScheme[travelFunc](ctx, def)
 	genScheme[tradeFunc](ctx, def)
+	genScheme[importanceFunc](ctx, def)
+	genScheme[economicFunc](ctx, def)
+	genScheme[culturalExtFunc](ctx, def)
 
 	log.Info().Msg("world generation complete")
 	return def
@@ -193,14 +211,33 @@ func GenerateWorldSummary(ctx *util.TASContext, def *model.WorldDefinition, src
 	log.Info().Msg("generating world summary...")
 
 	summary := &model.WorldSummary{
-		Name:         defaultWorldName,
-		HexLocation:  defaultHexLocation,
+		Name:         def.Name,
+		HexLocation:  def.SubsectorLoc,
+		Seed:         ctx.Dice().Seed(),
 		ExtendedData: model.ExtendedWorldSummary{},
 	}
+	if summary.Name == "" {
+		summary.Name = defaultWorldName
+	}
+	if summary.HexLocation == "" {
+		summary.HexLocation = defaultHexLocation
+	}
+
+	//look up each table entry once. A parsed UWP may hold values beyond the generation tables, so missing entries are tolerated
+	starport := tableEntry(ctx, "starport", src.WorldStarport, def.Starport.Value)
+	size := tableEntry(ctx, "size", src.WorldSize, def.Size)
+	atmo := tableEntry(ctx, "atmosphere", src.WorldAtmo, def.Atmosphere)
+	temp := tableEntry(ctx, "temperature", src.WorldTemperatures, def.Temperature)
+	hydro := tableEntry(ctx, "hydrographics", src.WorldHydro, def.Hydrographics)
+	pop := tableEntry(ctx, "population", src.WorldPop, def.Population)
+	gov := tableEntry(ctx, "government", src.WorldGov, def.Government)
+	culture := tableEntry(ctx, "culture", src.WorldCulture, def.Culture)
+	law := tableEntry(ctx, "law level", src.WorldLaw, def.LawLevel)
+	tech := tableEntry(ctx, "tech level", src.TechLevel, def.TechLevel)
 
 	//----------------------------------------
 	//core data - forms the UWP
-	summary.Starport = src.WorldStarport[def.Starport.Value].Code
+	summary.Starport = starport.Code
 	summary.Size = toHex(ctx, def.Size)
 	summary.Atmosphere = toHex(ctx, def.Atmosphere)
 	summary.Hydrographics = toHex(ctx, def.Hydrographics)
@@ -217,16 +254,25 @@ func GenerateWorldSummary(ctx *util.TASContext, def *model.WorldDefinition, src
 	}
 	summary.Bases = bases
 
-	//trade codes use abbreviation from lookup as all caps
+	//trade codes are shown as all caps
 	codes := make([]string, 0, len(def.TradeCodes))
+	codeDetails := make([]model.ExtendedTradeCodeSummary, 0, len(def.TradeCodes))
 	for _, c := range def.TradeCodes {
-		code := strings.ToUpper(src.WorldTradeCodes[c].Abbreviation)
-		codes = append(codes, code)
+		tc, ok := src.WorldTradeCodes.ByCode(c)
+		if !ok {
+			log.Warn().Str("trade-code", c).Msg("unknown trade code ignored")
+			continue
+		}
+		codes = append(codes, strings.ToUpper(tc.Code))
+		codeDetails = append(codeDetails, model.ExtendedTradeCodeSummary{Code: tc.Code, Name: tc.Name, Description: tc.Description})
 	}
 	summary.TradeCodes = codes
+	summary.ExtendedData.TradeCodeDetails = codeDetails
 
 	//travel zone is caps of first letter of given travel zone
-	summary.TravelZone = strings.ToUpper(def.TravelZone[0:1])
+	if def.TravelZone != "" {
+		summary.TravelZone = strings.ToUpper(def.TravelZone[0:1])
+	}
 
 	//----------------------------------------
 	//extended data - full info on each element in UWP
@@ -234,9 +280,9 @@ func GenerateWorldSummary(ctx *util.TASContext, def *model.WorldDefinition, src
 
 	// extended starport
 	esps := model.ExtendedStarportSummary{
-		Quality:      src.WorldStarport[def.Starport.Value].Quality,
-		Fuel:         src.WorldStarport[def.Starport.Value].Fuel,
-		Facilities:   src.WorldStarport[def.Starport.Value].Facilities,
+		Quality:      starport.Quality,
+		Fuel:         starport.Fuel,
+		Facilities:   starport.Facilities,
 		HasHighport:  "no",
 		BerthingCost: strconv.Itoa(def.Starport.BerthingCost) + h.CreditsAbbreviation,
 	}
@@ -247,42 +293,42 @@ func GenerateWorldSummary(ctx *util.TASContext, def *model.WorldDefinition, src
 
 	//extended size
 	ess := model.ExtendedSizeSummary{
-		Diameter: src.WorldSize[def.Size].Diameter,
-		Gravity:  src.WorldSize[def.Size].Gravity,
+		Diameter: size.Diameter,
+		Gravity:  size.Gravity,
 	}
 	summary.ExtendedData.SizeDetails = ess
 
 	//extended atmosphere
 	eas := model.ExetendedAtmosphereSummary{
-		Composition:               src.WorldAtmo[def.Atmosphere].Composition,
-		Pressure:                  src.WorldAtmo[def.Atmosphere].Pressure,
-		GearRequired:              src.WorldAtmo[def.Atmosphere].GearRequired,
-		TemperatureClassification: src.WorldTemperatures[def.Temperature].Type,
-		AverageTemperature:        src.WorldTemperatures[def.Temperature].AverageTemperature,
-		TemperatureDescription:    src.WorldTemperatures[def.Temperature].Description,
+		Composition:               atmo.Composition,
+		Pressure:                  atmo.Pressure,
+		GearRequired:              atmo.GearRequired,
+		TemperatureClassification: temp.Type,
+		AverageTemperature:        temp.AverageTemperature,
+		TemperatureDescription:    temp.Description,
 		HabitabilityZone:          def.HabitabilityZone,
 	}
 	summary.ExtendedData.AtmosphereDetails = eas
 
 	//extended hydrographics
 	ehs := model.ExtendedHydrographicsSummary{
-		Percentage:  src.WorldHydro[def.Hydrographics].Percentage,
-		Description: src.WorldHydro[def.Hydrographics].Description,
+		Percentage:  hydro.Percentage,
+		Description: hydro.Description,
 	}
 	summary.ExtendedData.HydrographicsDetails = ehs
 
 	//estended population
 	eps := model.ExtendedPopulationSummary{
-		Inhabitants: src.WorldPop[def.Population].Inhabitants,
+		Inhabitants: pop.Inhabitants,
 	}
 	summary.ExtendedData.PopulationDetails = eps
 
 	//extended government
 	egs := model.ExtendedGovernmentSummary{
-		Type:        src.WorldGov[def.Government].Type,
-		Description: src.WorldGov[def.Government].Description,
-		Example:     src.WorldGov[def.Government].Example,
-		Contraband:  src.WorldGov[def.Government].Contraband,
+		Type:        gov.Type,
+		Description: gov.Description,
+		Example:     gov.Example,
+		Contraband:  gov.Contraband,
 	}
 	summary.ExtendedData.GovernmentDetails = egs
 
@@ -290,16 +336,17 @@ func GenerateWorldSummary(ctx *util.TASContext, def *model.WorldDefinition, src
 	if len(def.Factions) > 0 {
 		factionList := make([]model.ExtendedFactionsSummary, 0, len(def.Factions))
 		for _, f := range def.Factions {
+			factionGov := tableEntry(ctx, "government", src.WorldGov, f.GovernmentStyle)
 			fctn := model.ExtendedFactionsSummary{
-				Government:       toHex(ctx, src.WorldGov[f.GovernmentStyle].Value),
-				RelativeStrength: src.WorldFactions[f.RelativeStrength].RelativeStrength,
+				Government:       toHex(ctx, f.GovernmentStyle),
+				RelativeStrength: tableEntry(ctx, "factions", src.WorldFactions, f.RelativeStrength).RelativeStrength,
 			}
 
 			fegv := model.ExtendedGovernmentSummary{
-				Type:        src.WorldGov[f.GovernmentStyle].Type,
-				Description: src.WorldGov[f.GovernmentStyle].Description,
-				Example:     src.WorldGov[f.GovernmentStyle].Example,
-				Contraband:  src.WorldGov[f.GovernmentStyle].Contraband,
+				Type:        factionGov.Type,
+				Description: factionGov.Description,
+				Example:     factionGov.Example,
+				Contraband:  factionGov.Contraband,
 			}
 			fctn.GovernmentDetails = fegv
 			factionList = append(factionList, fctn)
@@ -309,22 +356,22 @@ func GenerateWorldSummary(ctx *util.TASContext, def *model.WorldDefinition, src
 
 	//extended culture
 	ecs := model.ExtendedCultureSummary{
-		Type:        src.WorldCulture[def.Culture].Type,
-		Description: src.WorldCulture[def.Culture].Culture,
+		Type:        culture.Type,
+		Description: culture.Culture,
 	}
 	summary.ExtendedData.CulturDetails = ecs
 
 	//extended law level
 	els := model.ExtendedLawSummary{
-		BannedWeapons: src.WorldLaw[def.LawLevel].BannedWeapons,
-		BannedArmor:   src.WorldLaw[def.LawLevel].BannedArmor,
+		BannedWeapons: law.BannedWeapons,
+		BannedArmor:   law.BannedArmor,
 	}
 	summary.ExtendedData.LawDetails = els
 
 	//extended tech level
 	ets := model.ExtendedTechLevelSummary{
-		Catagory:    src.TechLevel[def.TechLevel].Catagory,
-		Description: src.TechLevel[def.TechLevel].Description,
+		Catagory:    tech.Catagory,
+		Description: tech.Description,
 	}
 	summary.ExtendedData.TechDetails = ets
 
@@ -333,18 +380,156 @@ func GenerateWorldSummary(ctx *util.TASContext, def *model.WorldDefinition, src
 	for i := 0; i < len(def.Bases); i++ {
 		baseType := def.Bases[i]
 		base := model.ExtendedBaseSummary{
-			Type:        baseType,
-			Description: src.WorldBases[baseType].Description,
+			Type: baseType,
+		}
+		if b, ok := src.WorldBases[baseType]; ok {
+			base.Description = b.Description
 		}
 		baseDetails = append(baseDetails, base)
 	}
 	summary.ExtendedData.BaseDetails = baseDetails
 
+	//star system - not known for a parsed UWP
+	if sys := def.System; sys != nil {
+		summary.PBG = sys.PBG(def.Population)
+		summary.Stars = sys.Stars()
+		ess := &model.ExtendedSystemSummary{
+			Primary:            sys.Primary.String(),
+			Companions:         make([]string, 0, len(sys.Companions)),
+			PlanetoidBelts:     sys.PlanetoidBelts,
+			GasGiants:          sys.GasGiants,
+			HabitableZoneOrbit: sys.HabitableZoneOrbit,
+			MainworldOrbit:     sys.MainworldOrbit,
+			Orbits:             make([]model.ExtendedOrbitSummary, 0, len(sys.Orbits)),
+		}
+		for _, c := range sys.Companions {
+			companion := c.String()
+			if c.Position != "" {
+				companion += h.SP + "(" + c.Position + ")"
+			}
+			ess.Companions = append(ess.Companions, companion)
+		}
+		for _, o := range sys.Orbits {
+			ess.Orbits = append(ess.Orbits, model.ExtendedOrbitSummary{Orbit: o.Number, Zone: o.Zone, Contents: o.Contents})
+		}
+		summary.ExtendedData.SystemDetails = ess
+	}
+
+	//T5 extensions - these are optional as a parsed UWP may not have them
+	if ext := def.Extensions; ext != nil {
+		summary.Importance = ext.ImportanceString()
+		summary.ExtendedData.ImportanceDetails = &model.ExtendedImportanceSummary{
+			Value:       ext.Importance,
+			Description: importanceDescription(ext.Importance),
+		}
+		if ex := ext.Economic; ex != nil {
+			summary.Economic = ext.EconomicString()
+			summary.ExtendedData.EconomicDetails = &model.ExtendedEconomicSummary{
+				Resources:      ex.Resources,
+				Labor:          ex.Labor,
+				Infrastructure: ex.Infrastructure,
+				Efficiency:     ex.Efficiency,
+			}
+		}
+		if cx := ext.Cultural; cx != nil {
+			summary.Cultural = ext.CulturalString()
+			summary.ExtendedData.CulturalDetails = &model.ExtendedCulturalSummary{
+				Heterogeneity: cx.Heterogeneity,
+				Acceptance:    cx.Acceptance,
+				Strangeness:   cx.Strangeness,
+				Symbols:       cx.Symbols,
+			}
+		}
+	}
+
 	log.Info().Msg("world summary complete")
 
 	return summary, nil
 }
 
+func importanceDescription(ix int) string {
+	switch {
+	case ix <= -1:
+		return "unimportant"
+	case ix <= 1:
+		return "ordinary"
+	case ix <= 3:
+		return "important"
+	default:
+		return "very important"
+	}
+}
+
+// tableEntry finds the entry for a value in one of the world tables. Missing entries are logged and an
+// empty entry is returned so that worlds read from a UWP can still be described
+func tableEntry[V any](ctx *util.TASContext, table string, m map[int]*V, value int) *V {
+	if e, ok := m[value]; ok {
+		return e
+	}
+	ctx.Logger().Warn().Str("table", table).Int("value", value).Msg("no table entry for value")
+	return new(V)
+}
+
+// LoadTradeCodes loads only the trade code catalogue, for commands that use trade codes but do not describe worlds
+func LoadTradeCodes(ctx *util.TASContext) (model.WorldTradeCodeMap, error) {
+
+	log := ctx.Logger()
+
+	fileData := util.IngestFiles("data/", []string{worldTradeCodeFile})
+	fd := fileData[worldTradeCodeFile]
+	if !fd.Ok() {
+		log.Error().Err(fd.Err).Str("filename", fd.Name).Msg("unable to load the trade code catalogue")
+		return nil, errors.New(h.UnableToContinueBecauseOfErrors)
+	}
+
+	codes, err := model.WorldTradeCodesFromFile(fd.Data)
+	if err != nil {
+		log.Error().Err(err).Str("filename", worldTradeCodeFile).Msg("unable to parse the trade code catalogue")
+		return nil, err
+	}
+	return codes, nil
+}
+
+// LoadStarports loads only the starport table, for commands that need starport facilities and costs
+func LoadStarports(ctx *util.TASContext) (model.WorldStarportMap, error) {
+
+	log := ctx.Logger()
+
+	fileData := util.IngestFiles("data/", []string{worldStarportFile})
+	fd := fileData[worldStarportFile]
+	if !fd.Ok() {
+		log.Error().Err(fd.Err).Str("filename", fd.Name).Msg("unable to load the starport table")
+		return nil, errors.New(h.UnableToContinueBecauseOfErrors)
+	}
+
+	starports, err := model.WorldStarportsFromFile(fd.Data)
+	if err != nil {
+		log.Error().Err(err).Str("filename", worldStarportFile).Msg("unable to parse the starport table")
+		return nil, err
+	}
+	return starports, nil
+}
+
+// LoadWorldSizes loads only the world size table, for commands that need world diameters
+func LoadWorldSizes(ctx *util.TASContext) (model.WorldSizeMap, error) {
+
+	log := ctx.Logger()
+
+	fileData := util.IngestFiles("data/", []string{worldSizeFile})
+	fd := fileData[worldSizeFile]
+	if !fd.Ok() {
+		log.Error().Err(fd.Err).Str("filename", fd.Name).Msg("unable to load the world size table")
+		return nil, errors.New(h.UnableToContinueBecauseOfErrors)
+	}
+
+	sizes, err := model.WorldSizesFromFile(fd.Data)
+	if err != nil {
+		log.Error().Err(err).Str("filename", worldSizeFile).Msg("unable to parse the world size table")
+		return nil, err
+	}
+	return sizes, nil
+}
+
 func LoadWorldSourceData(ctx *util.TASContext) (*model.WorldSource, error) {
 
 	log := ctx.Logger()
@@ -496,7 +681,22 @@ func BuildLongDescription(ctx *util.TASContext, summary *model.WorldSummary) {
 		tzone = "Green"
 	}
 
-	sb.WriteString("UWP:" + h.SP + summary.ToUWP())
+	sb.WriteString("UWP:" + h.SP + summary.ToUWP(false))
+	if sys := summary.ExtendedData.SystemDetails; sys != nil {
+		sb.WriteString(h.NL + "Star System")
+		sb.WriteString(h.NL + h.TAB + "Primary Star:" + h.SP + sys.Primary)
+		for _, c := range sys.Companions {
+			sb.WriteString(h.NL + h.TAB + "Companion Star:" + h.SP + c)
+		}
+		sb.WriteString(h.NL + h.TAB + "PBG (population multiplier, planetoid belts, gas giants):" + h.SP + summary.PBG)
+		if len(sys.Orbits) > 0 {
+			sb.WriteString(h.NL + h.TAB + "Orbits")
+			for _, o := range sys.Orbits {
+				sb.WriteString(h.NL + h.TAB + h.TAB + fmt.Sprintf("%2d %-10s %s", o.Orbit, o.Zone, o.Contents))
+			}
+		}
+		sb.WriteString(h.NL)
+	}
 	sb.WriteString(h.NL + "Starport")
 	sb.WriteString(h.NL + h.TAB + "Classification:" + h.SP + summary.Starport)
 	sb.WriteString(h.NL + h.TAB + "Quality:" + h.SP + summary.ExtendedData.StarportDetails.Quality)
@@ -574,23 +774,70 @@ func BuildLongDescription(ctx *util.TASContext, summary *model.WorldSummary) {
 		codes := strings.Join(summary.TradeCodes, ",")
 		sb.WriteString(h.NL)
 		sb.WriteString(h.NL + "Trade Codes:" + h.SP + codes)
+		for _, tc := range summary.ExtendedData.TradeCodeDetails {
+			sb.WriteString(h.NL + h.TAB + tc.Code + h.SP + "(" + tc.Name + "):" + h.SP + tc.Description)
+		}
+	}
+
+	if ix := summary.ExtendedData.ImportanceDetails; ix != nil {
+		sb.WriteString(h.NL)
+		sb.WriteString(h.NL + "Importance:" + h.SP + summary.Importance)
+		sb.WriteString(h.NL + h.TAB + "This world is" + h.SP + ix.Description + h.SP + "to the region around it")
 	}
 
-	summary.UWP = summary.ToUWP()
+	if ex := summary.ExtendedData.EconomicDetails; ex != nil {
+		sb.WriteString(h.NL)
+		sb.WriteString(h.NL + "Economics:" + h.SP + summary.Economic)
+		sb.WriteString(h.NL + h.TAB + "Resources available:" + h.SP + toHex(ctx, ex.Resources))
+		sb.WriteString(h.NL + h.TAB + "Labor force (population digit less one):" + h.SP + toHex(ctx, ex.Labor))
+		sb.WriteString(h.NL + h.TAB + "Infrastructure:" + h.SP + toHex(ctx, ex.Infrastructure))
+		sb.WriteString(h.NL + h.TAB + "Efficiency:" + h.SP + fmt.Sprintf("%+d", ex.Efficiency))
+	}
+
+	if cx := summary.ExtendedData.CulturalDetails; cx != nil {
+		sb.WriteString(h.NL)
+		sb.WriteString(h.NL + "Cultural Extension:" + h.SP + summary.Cultural)
+		sb.WriteString(h.NL + h.TAB + "Heterogeneity (how varied the population is):" + h.SP + toHex(ctx, cx.Heterogeneity))
+		sb.WriteString(h.NL + h.TAB + "Acceptance (how welcoming the population is to offworlders):" + h.SP + toHex(ctx, cx.Acceptance))
+		sb.WriteString(h.NL + h.TAB + "Strangeness (how alien the culture seems to visitors):" + h.SP + toHex(ctx, cx.Strangeness))
+		sb.WriteString(h.NL + h.TAB + "Symbols (how abstract the common symbols of the culture are):" + h.SP + toHex(ctx, cx.Symbols))
+	}
+
+	if len(summary.Audit) > 0 {
+		sb.WriteString(h.NL)
+		sb.WriteString(h.NL + "Dice Rolls Used To Generate This World")
+		sb.WriteString(AuditDescription(summary.Audit, h.TAB))
+	}
+
+	summary.UWP = summary.ToUWP(false)
 	summary.ExtendedData.LongDescription = sb.String()
 }
 
+// AuditDescription lists each audited roll on its own line (along with the reason for each modifier) using the given indent
+func AuditDescription(audit []*util.AuditEntry, indent string) string {
+	var sb strings.Builder
+
+	for _, a := range audit {
+		sb.WriteString(h.NL + indent + a.String())
+		for _, r := range a.Reasons {
+			sb.WriteString(h.NL + indent + h.TAB + r)
+		}
+	}
+	return sb.String()
+}
+
 func writeOutput(ctx *util.TASContext, summary *model.WorldSummary) {
 
 	//get flags
 	useLongform, _ := ctx.Config().Flags.GetBool(LongformOutputFlagName)
 	writeToFile, _ := ctx.Config().Flags.GetBool(util.ToFileFlagName)
+	withExtensions, _ := ctx.Config().Flags.GetBool(ExtensionsFlagName)
 
 	if useLongform {
 		fmt.Println(summary.ExtendedData.LongDescription)
 		return
 	} else {
-		fmt.Println(summary.ToUWP())
+		fmt.Println(summary.ToUWP(withExtensions))
 	}
 
 	if writeToFile {
@@ -599,7 +846,7 @@ func writeOutput(ctx *util.TASContext, summary *model.WorldSummary) {
 }
 
 func toHex(ctx *util.TASContext, i int) string {
-	h, err := util.IntAsHexString(i)
+	h, err := util.IntAsEHexString(i)
 	if err != nil {
 		h = "error!"
 		ctx.Logger().Error().Err(err).Send()
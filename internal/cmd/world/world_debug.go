@@ -61,7 +61,7 @@ func debugWorldGeneration(cmd *cobra.Command, args []string) {
 	log := util.NewLogger()
 	ctx := util.NewContext().
 		WithLogger(log).
-		WithDice().
+		WithDice(cfg.Seed()).
 		WithConfig(cfg)
 
 	//determine which worldgen scheme to use
@@ -73,6 +73,12 @@ func debugWorldGeneration(cmd *cobra.Command, args []string) {
 	}
 	log.Info().Str("scheme", schemeAsString).Msg("scheme used for world generation")
 
+	//load the data we need to generate a world
+	src, err := LoadWorldSourceData(ctx)
+	if err != nil {
+		return
+	}
+
 	//prep data store to hold the randomized worlds
 	numberOfWorldsToGenerate := subsectorLoopsToRun
 	useMax, _ := cfg.Flags.GetBool(MaxLoopSizeFlagName)
@@ -90,7 +96,7 @@ func debugWorldGeneration(cmd *cobra.Command, args []string) {
 
 	//generate the planets
 	for i := 0; i < numberOfWorldsToGenerate; i++ {
-		def := GenerateWorld(ctx, schemeType)
+		def := GenerateWorld(ctx, schemeType, src)
 		dataStore = append(dataStore, def)
 	}
 
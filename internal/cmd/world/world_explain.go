@@ -0,0 +1,96 @@
+package world
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	h "tas/internal/cmd/helpers"
+	"tas/internal/model"
+	"tas/internal/util"
+
+	"github.com/spf13/cobra"
+)
+
+var WorldExplainCmdConfig = &cobra.Command{
+
+	Use:   "explain <UWP>",
+	Short: "decodes an existing UWP (e.g. 'Corgi 0101 CA6A643-9 N RI WA A') into a detailed world description",
+	Run:   explainWorld,
+
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return fmt.Errorf("a UWP is required, such as 'Corgi 0101 CA6A643-9 N RI WA A'")
+		}
+		return nil
+	},
+}
+
+func explainWorld(cmd *cobra.Command, args []string) {
+
+	//create a config to hold all data passed into this call
+	cfg, err := util.NewTASConfig().
+		WithArgs(args).
+		WithCmd(cmd)
+	if err != nil {
+		fmt.Println()
+		fmt.Printf("Unable to create config. This is a critical error: %s\n", err)
+		fmt.Println()
+		return
+	}
+
+	//build a context to make all data easily available between calls
+	loglevel, _ := cfg.Flags.GetString(util.LogLevelFlagName)
+	log := util.NewLogger(loglevel)
+	ctx := util.NewContext().
+		WithLogger(log).
+		WithDice(cfg.Seed()).
+		WithConfig(cfg)
+
+	//load the data we need to interpret & output a world
+	src, err := LoadWorldSourceData(ctx)
+	if err != nil {
+		return
+	}
+
+	//the UWP may be given as one quoted argument or as several unquoted ones
+	def, err := ParseWorldUWP(strings.Join(args, h.SP), src)
+	if err != nil {
+		log.Error().Err(err).Msg("unable to parse UWP")
+		return
+	}
+
+	summary, err := GenerateWorldSummary(ctx, def, src)
+	if err != nil {
+		log.Error().Err(err).Msg("unable to create world summary")
+		return
+	}
+	BuildLongDescription(ctx, summary)
+
+	log.Debug().Object("UWP", summary).Send()
+	fmt.Println(summary.ExtendedData.LongDescription)
+
+	writeToFile, _ := cfg.Flags.GetBool(util.ToFileFlagName)
+	if writeToFile {
+		h.WrappedJSONFileWriter(ctx, summary, summary.ToFileName())
+	}
+}
+
+// ParseWorldUWP parses a UWP into a world definition ready to be summarized. Values that a UWP does not
+// record (temperature, culture, factions etc) are marked as unknown. All field errors are joined into one
+func ParseWorldUWP(uwp string, src *model.WorldSource) (*model.WorldDefinition, error) {
+
+	def, errs := model.ParseUWP(uwp, src.WorldTradeCodes)
+	if len(errs) > 0 {
+		msgs := make([]string, 0, len(errs))
+		for _, e := range errs {
+			msgs = append(msgs, e.Error())
+		}
+		return nil, errors.New(strings.Join(msgs, "; "))
+	}
+
+	def.Temperature = specialTempCodeForUnknown
+	def.HabitabilityZone = "unknown"
+	def.Culture = specialCultureCodeForNoPop
+	return def, nil
+}
@@ -0,0 +1,196 @@
+// Package hexgrid works with hex locations on Traveller's sector maps. Maps use an 'odd-q' offset
+// layout: hexes are in columns, with every other column (02, 04, ...) shifted down by half a hex.
+// Hexes are identified within a sector by a 4-digit column/row location (e.g. 0304) and sectors are
+// placed relative to each other by an offset, so distances work across subsector and sector boundaries.
+package hexgrid
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// a sector is 32 columns by 40 rows of hexes
+const (
+	SectorCols = 32
+	SectorRows = 40
+)
+
+// SectorOffset is the position of a sector relative to some other sector (0,0). X increases to
+// trailing (right) and Y increases to rimward (down)
+type SectorOffset struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// Hex is a hex location anywhere on the map. Col and Row are global, so hex 0101 of sector (0,0)
+// is col 1, row 1 and hex 0101 of sector (1,0) is col 33, row 1
+type Hex struct {
+	Col int
+	Row int
+}
+
+// Parse reads a 4-digit hex location such as a WorldSummary.HexLocation. The location is in sector (0,0)
+// unless a sector offset is given
+func Parse(location string, sector ...SectorOffset) (Hex, error) {
+	if len(location) != 4 {
+		return Hex{}, fmt.Errorf("hex location '%s' must be 4 digits, e.g. 0304", location)
+	}
+	col, errCol := strconv.Atoi(location[0:2])
+	row, errRow := strconv.Atoi(location[2:4])
+	if errCol != nil || errRow != nil {
+		return Hex{}, fmt.Errorf("hex location '%s' must be 4 digits, e.g. 0304", location)
+	}
+	if col < 1 || col > SectorCols || row < 1 || row > SectorRows {
+		return Hex{}, fmt.Errorf("hex location '%s' is outside a sector (0101 to %02d%02d)", location, SectorCols, SectorRows)
+	}
+
+	offset := SectorOffset{}
+	if len(sector) > 0 {
+		offset = sector[0]
+	}
+	return Hex{Col: offset.X*SectorCols + col, Row: offset.Y*SectorRows + row}, nil
+}
+
+// Sector returns the offset of the sector holding this hex
+func (h Hex) Sector() SectorOffset {
+	return SectorOffset{X: floorDiv(h.Col-1, SectorCols), Y: floorDiv(h.Row-1, SectorRows)}
+}
+
+// Location returns the 4-digit hex location within the hex's sector e.g. 0304
+func (h Hex) Location() string {
+	s := h.Sector()
+	return fmt.Sprintf("%02d%02d", h.Col-s.X*SectorCols, h.Row-s.Y*SectorRows)
+}
+
+func (h Hex) String() string {
+	s := h.Sector()
+	if s.X == 0 && s.Y == 0 {
+		return h.Location()
+	}
+	return fmt.Sprintf("%s (sector %d,%d)", h.Location(), s.X, s.Y)
+}
+
+// Distance is the number of parsecs (jumps of 1) between two hexes
+func Distance(a Hex, b Hex) int {
+	return a.cube().distance(b.cube())
+}
+
+// WithinRange returns all hexes within n parsecs of the center hex (jump-n), including the center
+func WithinRange(center Hex, n int) []Hex {
+	if n < 0 {
+		return []Hex{}
+	}
+	c := center.cube()
+	hexes := make([]Hex, 0, 3*n*(n+1)+1)
+	for dx := -n; dx <= n; dx++ {
+		for dy := max(-n, -dx-n); dy <= min(n, -dx+n); dy++ {
+			hexes = append(hexes, cube{x: c.x + dx, y: c.y + dy, z: c.z - dx - dy}.hex())
+		}
+	}
+	return hexes
+}
+
+// Line returns the hexes on the straight line between two hexes, including both ends. There are
+// Distance(a, b)+1 hexes in the line
+func Line(a Hex, b Hex) []Hex {
+	ca, cb := a.cube(), b.cube()
+	n := ca.distance(cb)
+	hexes := make([]Hex, 0, n+1)
+	for i := 0; i <= n; i++ {
+		t := 0.0
+		if n > 0 {
+			t = float64(i) / float64(n)
+		}
+		//nudge the end points slightly so lines along hex edges always fall the same way
+		hexes = append(hexes, cubeRound(
+			lerp(float64(ca.x)+1e-6, float64(cb.x)+1e-6, t),
+			lerp(float64(ca.y)+1e-6, float64(cb.y)+1e-6, t),
+			lerp(float64(ca.z)-2e-6, float64(cb.z)-2e-6, t),
+		).hex())
+	}
+	return hexes
+}
+
+// cube coordinates make the geometry simple: x + y + z is always 0 and the distance is the largest difference
+type cube struct {
+	x, y, z int
+}
+
+func (h Hex) cube() cube {
+	q := h.Col - 1
+	r := h.Row - 1
+	x := q
+	z := r - (q-(q&1))/2
+	return cube{x: x, y: -x - z, z: z}
+}
+
+func (c cube) hex() Hex {
+	q := c.x
+	r := c.z + (c.x-(c.x&1))/2
+	return Hex{Col: q + 1, Row: r + 1}
+}
+
+func (c cube) distance(o cube) int {
+	return max(abs(c.x-o.x), max(abs(c.y-o.y), abs(c.z-o.z)))
+}
+
+func cubeRound(fx, fy, fz float64) cube {
+	x, y, z := round(fx), round(fy), round(fz)
+	dx, dy, dz := absf(float64(x)-fx), absf(float64(y)-fy), absf(float64(z)-fz)
+	switch {
+	case dx > dy && dx > dz:
+		x = -y - z
+	case dy > dz:
+		y = -x - z
+	default:
+		z = -x - y
+	}
+	return cube{x: x, y: y, z: z}
+}
+
+func lerp(a, b, t float64) float64 {
+	return a + (b-a)*t
+}
+
+func round(f float64) int {
+	if f < 0 {
+		return -int(-f + 0.5)
+	}
+	return int(f + 0.5)
+}
+
+func floorDiv(a, b int) int {
+	d := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		d--
+	}
+	return d
+}
+
+func abs(i int) int {
+	if i < 0 {
+		return -i
+	}
+	return i
+}
+
+func absf(f float64) float64 {
+	if f < 0 {
+		return -f
+	}
+	return f
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
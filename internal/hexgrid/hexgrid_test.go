@@ -0,0 +1,75 @@
+package hexgrid
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mustParse(t *testing.T, location string, sector ...SectorOffset) Hex {
+	h, err := Parse(location, sector...)
+	assert.NoError(t, err)
+	return h
+}
+
+func TestDistance(t *testing.T) {
+
+	tests := []struct {
+		from, to string
+		want     int
+	}{
+		{"0101", "0101", 0},
+		{"0101", "0102", 1},
+		{"0101", "0201", 1}, //even columns are shifted down, so 0201 is next to 0101
+		{"0201", "0102", 1},
+		{"0201", "0101", 1},
+		{"0101", "0202", 2},
+		{"0101", "0301", 2},
+		{"0101", "0810", 13},
+		{"0110", "0111", 1}, //across a subsector boundary
+		{"0101", "3240", 55},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, Distance(mustParse(t, tt.from), mustParse(t, tt.to)), "%s to %s", tt.from, tt.to)
+	}
+
+	//across a sector boundary
+	assert.Equal(t, 1, Distance(mustParse(t, "3201"), mustParse(t, "0101", SectorOffset{X: 1})))
+	assert.Equal(t, 1, Distance(mustParse(t, "0140"), mustParse(t, "0101", SectorOffset{Y: 1})))
+	assert.Equal(t, 1, Distance(mustParse(t, "0101"), mustParse(t, "3201", SectorOffset{X: -1})))
+}
+
+func TestWithinRange(t *testing.T) {
+
+	center := mustParse(t, "0505")
+	for n := 0; n <= 4; n++ {
+		hexes := WithinRange(center, n)
+		assert.Len(t, hexes, 3*n*(n+1)+1)
+		for _, h := range hexes {
+			assert.LessOrEqual(t, Distance(center, h), n)
+		}
+	}
+}
+
+func TestLine(t *testing.T) {
+
+	a := mustParse(t, "0101")
+	b := mustParse(t, "0604")
+	line := Line(a, b)
+	assert.Len(t, line, Distance(a, b)+1)
+	assert.Equal(t, a, line[0])
+	assert.Equal(t, b, line[len(line)-1])
+	for i := 1; i < len(line); i++ {
+		assert.Equal(t, 1, Distance(line[i-1], line[i]))
+	}
+}
+
+func TestLocation(t *testing.T) {
+
+	h := mustParse(t, "0304", SectorOffset{X: -1, Y: 2})
+	assert.Equal(t, SectorOffset{X: -1, Y: 2}, h.Sector())
+	assert.Equal(t, "0304", h.Location())
+
+	_, err := Parse("3341")
+	assert.Error(t, err)
+}
@@ -0,0 +1,65 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+const (
+	// DaysPerYear is the length of the Imperial year. Days are numbered 001 to 365, with 001 being Holiday
+	DaysPerYear = 365
+
+	hoursPerDay = 24
+)
+
+var imperialDatePattern = regexp.MustCompile(`^(\d{3})-(\d{1,4})$`)
+
+// ImperialDate is a date in the Imperial calendar, written as day-year (e.g. 001-1105)
+type ImperialDate struct {
+	Day  int
+	Year int
+}
+
+func ParseImperialDate(s string) (ImperialDate, error) {
+	m := imperialDatePattern.FindStringSubmatch(s)
+	if m == nil {
+		return ImperialDate{}, fmt.Errorf("invalid Imperial date: %s, expected day-year such as 001-1105", s)
+	}
+	day, _ := strconv.Atoi(m[1])
+	year, _ := strconv.Atoi(m[2])
+	if day < 1 || day > DaysPerYear {
+		return ImperialDate{}, fmt.Errorf("invalid Imperial date: %s, the day must be 001 to %d", s, DaysPerYear)
+	}
+	return ImperialDate{Day: day, Year: year}, nil
+}
+
+func (d ImperialDate) String() string {
+	return fmt.Sprintf("%03d-%d", d.Day, d.Year)
+}
+
+// AddDays is the date the given number of days later, or earlier if days is negative
+func (d ImperialDate) AddDays(days int) ImperialDate {
+	n := d.dayNumber() + days
+	return ImperialDate{Day: n%DaysPerYear + 1, Year: n / DaysPerYear}
+}
+
+// AddHours is the date after the given number of hours, with any part of a day counting as a whole day
+func (d ImperialDate) AddHours(hours int) ImperialDate {
+	return d.AddDays(HoursAsDays(hours))
+}
+
+// DaysSince is the number of days from the earlier date to this one
+func (d ImperialDate) DaysSince(earlier ImperialDate) int {
+	return d.dayNumber() - earlier.dayNumber()
+}
+
+// HoursAsDays is the number of days the hours take, with any part of a day counting as a whole day
+func HoursAsDays(hours int) int {
+	return (hours + hoursPerDay - 1) / hoursPerDay
+}
+
+// dayNumber counts the days since day 001 of year 0
+func (d ImperialDate) dayNumber() int {
+	return d.Year*DaysPerYear + d.Day - 1
+}
@@ -0,0 +1,33 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseImperialDate(t *testing.T) {
+	d, err := ParseImperialDate("001-1105")
+	assert.Nil(t, err)
+	assert.Equal(t, ImperialDate{Day: 1, Year: 1105}, d)
+	assert.Equal(t, "001-1105", d.String())
+
+	_, err = ParseImperialDate("366-1105")
+	assert.NotNil(t, err)
+	_, err = ParseImperialDate("1-1105")
+	assert.NotNil(t, err)
+}
+
+func TestImperialDateArithmetic(t *testing.T) {
+	d := ImperialDate{Day: 360, Year: 1105}
+
+	assert.Equal(t, "003-1106", d.AddDays(8).String())
+	assert.Equal(t, "355-1105", d.AddDays(-5).String())
+	assert.Equal(t, "001-1106", d.AddDays(6).String())
+
+	//a part day counts as a whole day
+	assert.Equal(t, "361-1105", d.AddHours(1).String())
+	assert.Equal(t, "362-1105", d.AddHours(25).String())
+
+	assert.Equal(t, 8, d.AddDays(8).DaysSince(d))
+}
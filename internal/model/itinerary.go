@@ -0,0 +1,59 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// the places a ship can take on fuel, best first. A starport may sell refined or unrefined fuel; skimming a gas
+// giant or drawing surface water gives unrefined fuel
+const (
+	FuelSourceRefined   = "refined"
+	FuelSourceUnrefined = "unrefined"
+	FuelSourceGasGiant  = "gas giant"
+	FuelSourceWater     = "water"
+)
+
+// ItineraryHop is one jump of a planned route. Refuel is where fuel is taken on at the world jumped to, if
+// anywhere, and ElapsedHours is the time since leaving the first world
+type ItineraryHop struct {
+	From         string `json:"from"`
+	FromHex      string `json:"from-hex"`
+	To           string `json:"to"`
+	ToHex        string `json:"to-hex"`
+	Parsecs      int    `json:"parsecs"`
+	Hours        int    `json:"hours"`
+	ElapsedHours int    `json:"elapsed-hours"`
+	Refuel       string `json:"refuel,omitempty"`
+}
+
+// Itinerary is a route planned between two worlds of a sector for a ship with a given jump rating and fuel
+type Itinerary struct {
+	Sector      string          `json:"sector"`
+	From        string          `json:"from-world"`
+	To          string          `json:"to-world"`
+	Ship        string          `json:"ship,omitempty"`
+	JumpRating  int             `json:"jump-rating"`
+	FuelParsecs int             `json:"fuel-parsecs"`
+	Thrust      int             `json:"thrust"`
+	Refined     bool            `json:"refined-fuel,omitempty"`
+	AvoidRed    bool            `json:"avoid-red-zones,omitempty"`
+	Hops        []*ItineraryHop `json:"hops"`
+	Parsecs     int             `json:"parsecs"`
+	TotalHours  int             `json:"total-hours"`
+	Notes       []string        `json:"notes,omitempty"`
+}
+
+func (i *Itinerary) ToFileName() string {
+	var sb strings.Builder
+
+	now := time.Now()
+
+	sb.WriteString("route")
+	sb.WriteString(us + i.From)
+	sb.WriteString(us + i.To)
+	sb.WriteString(ds + now.Format("20060102150405"))
+	sb.WriteString(".json")
+
+	return sb.String()
+}
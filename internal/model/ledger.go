@@ -0,0 +1,298 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// LedgerCargo is speculative cargo in the hold, with what was paid for it and where it was bought
+type LedgerCargo struct {
+	Goods       string `json:"goods"`
+	Tons        int    `json:"tons"`
+	PricePerTon int    `json:"price-per-ton"`
+	Origin      string `json:"origin"`
+	Date        string `json:"date,omitempty"`
+}
+
+// LedgerPassengers are passengers of one passage type travelling to the same world. Their fares are paid when they
+// board
+type LedgerPassengers struct {
+	PassageType string `json:"type"`
+	Count       int    `json:"count"`
+	Fare        int    `json:"fare"`
+	Destination string `json:"destination"`
+}
+
+// LedgerFreight is a lot of freight, which is paid for when it is delivered
+type LedgerFreight struct {
+	Tons        int    `json:"tons"`
+	Payment     int    `json:"payment"`
+	Destination string `json:"destination"`
+}
+
+// LedgerEntry records one change to the ledger and the credit balance after it
+type LedgerEntry struct {
+	Date    string `json:"date,omitempty"`
+	World   string `json:"world"`
+	Action  string `json:"action"`
+	Detail  string `json:"detail"`
+	Credits int    `json:"credits"`
+	Balance int    `json:"balance"`
+}
+
+// Ledger is the state of a campaign: where the ship is, when, how many credits it has and what it is carrying
+type Ledger struct {
+	World      string              `json:"world"`
+	Date       string              `json:"date"`
+	Credits    int                 `json:"credits"`
+	Cargo      []*LedgerCargo      `json:"cargo"`
+	Passengers []*LedgerPassengers `json:"passengers"`
+	Freight    []*LedgerFreight    `json:"freight"`
+	History    []*LedgerEntry      `json:"history"`
+}
+
+var passageTypes = []string{"high", "middle", "basic", "low"}
+
+func NewLedger(world string, date string, credits int) *Ledger {
+	l := &Ledger{
+		World:      world,
+		Date:       date,
+		Cargo:      make([]*LedgerCargo, 0),
+		Passengers: make([]*LedgerPassengers, 0),
+		Freight:    make([]*LedgerFreight, 0),
+		History:    make([]*LedgerEntry, 0),
+	}
+	l.record("start", fmt.Sprintf("ledger started at %s", world), credits)
+	return l
+}
+
+func LedgerFromFile(b []byte) (*Ledger, error) {
+	var l Ledger
+	err := json.Unmarshal(b, &l)
+	if err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+func (l *Ledger) ToFileName() string {
+	var sb strings.Builder
+
+	now := time.Now()
+
+	sb.WriteString("ledger")
+	sb.WriteString(us + l.World)
+	sb.WriteString(ds + now.Format("20060102150405"))
+	sb.WriteString(".json")
+
+	return sb.String()
+}
+
+func (l *Ledger) Validate() []error {
+	errs := make([]error, 0)
+
+	if strings.TrimSpace(l.World) == "" {
+		errs = append(errs, fmt.Errorf("the ledger has no current world"))
+	}
+	if _, err := ParseImperialDate(l.Date); err != nil {
+		errs = append(errs, err)
+	}
+	for _, c := range l.Cargo {
+		if c.Tons <= 0 || c.PricePerTon < 0 {
+			errs = append(errs, fmt.Errorf("invalid cargo of %s: %dT at Cr%d per ton", c.Goods, c.Tons, c.PricePerTon))
+		}
+	}
+	for _, p := range l.Passengers {
+		if !validPassageType(p.PassageType) || p.Count <= 0 || p.Fare < 0 {
+			errs = append(errs, fmt.Errorf("invalid passengers: %d %s to %s", p.Count, p.PassageType, p.Destination))
+		}
+	}
+	for _, f := range l.Freight {
+		if f.Tons <= 0 || f.Payment < 0 {
+			errs = append(errs, fmt.Errorf("invalid freight: %dT to %s", f.Tons, f.Destination))
+		}
+	}
+	return errs
+}
+
+// CargoTons is the tons of speculative cargo and freight in the hold
+func (l *Ledger) CargoTons() int {
+	tons := 0
+	for _, c := range l.Cargo {
+		tons += c.Tons
+	}
+	for _, f := range l.Freight {
+		tons += f.Tons
+	}
+	return tons
+}
+
+// Buy adds speculative cargo bought at the current world. The ship must have the credits to pay for it
+func (l *Ledger) Buy(goods string, tons int, pricePerTon int) error {
+	if tons <= 0 || pricePerTon < 0 {
+		return fmt.Errorf("cannot buy %dT at Cr%d per ton", tons, pricePerTon)
+	}
+	cost := tons * pricePerTon
+	if cost > l.Credits {
+		return fmt.Errorf("%dT of %s costs Cr%d but there is only Cr%d", tons, goods, cost, l.Credits)
+	}
+
+	l.Cargo = append(l.Cargo, &LedgerCargo{Goods: goods, Tons: tons, PricePerTon: pricePerTon, Origin: l.World, Date: l.Date})
+	l.record("buy", fmt.Sprintf("%dT of %s at Cr%d per ton", tons, goods, pricePerTon), -cost)
+	return nil
+}
+
+// Sell removes speculative cargo from the hold, the oldest first, and returns the profit made over what was paid
+// for it
+func (l *Ledger) Sell(goods string, tons int, pricePerTon int) (int, error) {
+	if tons <= 0 || pricePerTon < 0 {
+		return 0, fmt.Errorf("cannot sell %dT at Cr%d per ton", tons, pricePerTon)
+	}
+	held := 0
+	for _, c := range l.Cargo {
+		if strings.EqualFold(c.Goods, goods) {
+			held += c.Tons
+		}
+	}
+	if held < tons {
+		return 0, fmt.Errorf("cannot sell %dT of %s, only %dT is held", tons, goods, held)
+	}
+
+	paid := 0
+	left := tons
+	cargo := make([]*LedgerCargo, 0, len(l.Cargo))
+	for _, c := range l.Cargo {
+		if left > 0 && strings.EqualFold(c.Goods, goods) {
+			sold := minInt(left, c.Tons)
+			paid += sold * c.PricePerTon
+			c.Tons -= sold
+			left -= sold
+		}
+		if c.Tons > 0 {
+			cargo = append(cargo, c)
+		}
+	}
+	l.Cargo = cargo
+
+	income := tons * pricePerTon
+	l.record("sell", fmt.Sprintf("%dT of %s at Cr%d per ton (profit Cr%d)", tons, goods, pricePerTon, income-paid), income)
+	return income - paid, nil
+}
+
+// LoadPassengers takes on passengers bound for the destination, who pay their fares as they board
+func (l *Ledger) LoadPassengers(passageType string, count int, fare int, destination string) error {
+	passageType = strings.ToLower(passageType)
+	if !validPassageType(passageType) {
+		return fmt.Errorf("passage type must be one of %s", strings.Join(passageTypes, ", "))
+	}
+	if count <= 0 || fare < 0 {
+		return fmt.Errorf("cannot load %d passengers at Cr%d", count, fare)
+	}
+
+	l.Passengers = append(l.Passengers, &LedgerPassengers{PassageType: passageType, Count: count, Fare: fare, Destination: destination})
+	l.record("load", fmt.Sprintf("%d %s passengers to %s at Cr%d", count, passageType, destination, fare), count*fare)
+	return nil
+}
+
+// LoadFreight takes on a lot of freight bound for the destination. It is paid for when it is delivered
+func (l *Ledger) LoadFreight(tons int, payment int, destination string) error {
+	if tons <= 0 || payment < 0 {
+		return fmt.Errorf("cannot load %dT of freight paying Cr%d", tons, payment)
+	}
+
+	l.Freight = append(l.Freight, &LedgerFreight{Tons: tons, Payment: payment, Destination: destination})
+	l.record("load", fmt.Sprintf("%dT of freight to %s, Cr%d on delivery", tons, destination, payment), 0)
+	return nil
+}
+
+// Unload lets off the passengers and delivers the freight bound for the current world, and is paid for the
+// freight. It returns the number of passengers and tons unloaded
+func (l *Ledger) Unload() (int, int) {
+	passengers := 0
+	remaining := make([]*LedgerPassengers, 0, len(l.Passengers))
+	for _, p := range l.Passengers {
+		if strings.EqualFold(p.Destination, l.World) {
+			passengers += p.Count
+		} else {
+			remaining = append(remaining, p)
+		}
+	}
+	l.Passengers = remaining
+
+	tons, payment := 0, 0
+	freight := make([]*LedgerFreight, 0, len(l.Freight))
+	for _, f := range l.Freight {
+		if strings.EqualFold(f.Destination, l.World) {
+			tons += f.Tons
+			payment += f.Payment
+		} else {
+			freight = append(freight, f)
+		}
+	}
+	l.Freight = freight
+
+	if passengers > 0 || tons > 0 {
+		l.record("unload", fmt.Sprintf("%d passengers and %dT of freight", passengers, tons), payment)
+	}
+	return passengers, tons
+}
+
+// Jump moves the ship to another world, moving the date on by the hours the trip takes
+func (l *Ledger) Jump(world string, hours int) error {
+	date, err := ParseImperialDate(l.Date)
+	if err != nil {
+		return err
+	}
+	from := l.World
+	l.World = world
+	l.Date = date.AddHours(hours).String()
+	l.record("jump", fmt.Sprintf("from %s to %s (%d days)", from, world, HoursAsDays(hours)), 0)
+	return nil
+}
+
+// Wait moves the date on by the given days, such as those spent finding a supplier
+func (l *Ledger) Wait(days int, reason string) error {
+	if days <= 0 {
+		return fmt.Errorf("cannot wait %d days", days)
+	}
+	date, err := ParseImperialDate(l.Date)
+	if err != nil {
+		return err
+	}
+	l.Date = date.AddDays(days).String()
+	l.record("wait", fmt.Sprintf("%d days %s", days, reason), 0)
+	return nil
+}
+
+// Pay takes credits from the balance for a cost such as fuel, berthing or the mortgage. Costs must be paid, so the
+// balance may go below zero
+func (l *Ledger) Pay(amount int, item string) error {
+	if amount <= 0 {
+		return fmt.Errorf("cannot pay Cr%d", amount)
+	}
+	l.record("pay", item, -amount)
+	return nil
+}
+
+func (l *Ledger) record(action string, detail string, credits int) {
+	l.Credits += credits
+	l.History = append(l.History, &LedgerEntry{
+		Date:    l.Date,
+		World:   l.World,
+		Action:  action,
+		Detail:  detail,
+		Credits: credits,
+		Balance: l.Credits,
+	})
+}
+
+func validPassageType(passageType string) bool {
+	for _, p := range passageTypes {
+		if p == passageType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,49 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLedger(t *testing.T) {
+
+	l := NewLedger("Regina", "001-1105", 100000)
+	assert.Equal(t, 100000, l.Credits)
+
+	assert.NoError(t, l.Buy("Spices", 2, 10000))
+	assert.NoError(t, l.Buy("spices", 3, 12000))
+	assert.Error(t, l.Buy("Spices", 10, 10000))
+	assert.Equal(t, 44000, l.Credits)
+
+	//the oldest cargo is sold first
+	profit, err := l.Sell("SPICES", 3, 15000)
+	assert.NoError(t, err)
+	assert.Equal(t, 2*5000+3000, profit)
+	assert.Len(t, l.Cargo, 1)
+	assert.Equal(t, 2, l.Cargo[0].Tons)
+	_, err = l.Sell("Spices", 3, 15000)
+	assert.Error(t, err)
+
+	assert.NoError(t, l.LoadPassengers("High", 2, 9000, "Jenghe"))
+	assert.Error(t, l.LoadPassengers("steerage", 2, 9000, "Jenghe"))
+	assert.NoError(t, l.LoadFreight(10, 10000, "Jenghe"))
+	assert.NoError(t, l.LoadFreight(5, 5000, "Rhylanor"))
+	assert.Equal(t, 17, l.CargoTons())
+
+	assert.NoError(t, l.Jump("Jenghe", 170))
+	assert.Equal(t, "009-1105", l.Date)
+	passengers, tons := l.Unload()
+	assert.Equal(t, 2, passengers)
+	assert.Equal(t, 10, tons)
+	assert.Len(t, l.Freight, 1)
+	assert.Equal(t, 44000+45000+18000+10000, l.Credits)
+
+	assert.NoError(t, l.Wait(3, "finding a supplier"))
+	assert.Equal(t, "012-1105", l.Date)
+
+	assert.NoError(t, l.Pay(200000, "mortgage"))
+	assert.Less(t, l.Credits, 0)
+	assert.Empty(t, l.Validate())
+	assert.Equal(t, l.Credits, l.History[len(l.History)-1].Balance)
+}
@@ -0,0 +1,28 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	"tas/internal/util"
+)
+
+type RollSummary struct {
+	Expression string                       `json:"expression"`
+	DM         int                          `json:"dm"`
+	BoonOrBane string                       `json:"boon-or-bane,omitempty"`
+	Seed       int64                        `json:"seed"`
+	Rolls      []*util.DiceExpressionResult `json:"rolls"`
+}
+
+func (r *RollSummary) ToFileName() string {
+	var sb strings.Builder
+
+	now := time.Now()
+
+	sb.WriteString("roll")
+	sb.WriteString(ds + now.Format("20060102150405"))
+	sb.WriteString(".json")
+
+	return sb.String()
+}
@@ -0,0 +1,20 @@
+package model
+
+const (
+	RouteTypeXboat = "xboat"
+	RouteTypeTrade = "trade"
+)
+
+// Route links the worlds in two hexes of a sector. Xboat (communication) routes link the important worlds of a
+// sector and trade routes link worlds that trade regularly
+type Route struct {
+	Type    string `json:"type"`
+	From    string `json:"from"`
+	To      string `json:"to"`
+	Parsecs int    `json:"parsecs"`
+}
+
+// Touches is true if the route starts or ends in the given hex
+func (r *Route) Touches(hex string) bool {
+	return r.From == hex || r.To == hex
+}
@@ -1,15 +1,103 @@
 package model
 
+import (
+	"fmt"
+	"strconv"
+
+	"tas/internal/hexgrid"
+)
+
+// a sector (see hexgrid.SectorCols and hexgrid.SectorRows) is split into 16 subsectors (A-P) of 8 columns by 10
+// rows. Subsector A is top left and subsectors are lettered across then down
+const (
+	SubsectorCols = 8
+	SubsectorRows = 10
+
+	subsectorLetters = "ABCDEFGHIJKLMNOP"
+)
+
 type SectorWorld struct {
 	WorldSummaryData *WorldSummary `json:"world"`
-	HasGasGiant      bool          `json:"has-gas-giant"`
+	Subsector        string        `json:"subsector"`
+	Reroll           int           `json:"reroll,omitempty"`
+}
+
+type Subsector struct {
+	Letter string `json:"letter"`
+	Name   string `json:"name"`
+}
+
+// HasGasGiant is true if there is at least one gas giant in the world's system
+func (w *SectorWorld) HasGasGiant() bool {
+	sys := w.WorldSummaryData.ExtendedData.SystemDetails
+	return sys != nil && sys.GasGiants > 0
 }
 
 type Sector struct {
-	Name   string         `json:"name"`
-	Worlds []*SectorWorld `json:"worlds"`
+	Name       string         `json:"name"`
+	Seed       int64          `json:"seed"`
+	Subsectors []*Subsector   `json:"subsectors"`
+	Worlds     []*SectorWorld `json:"worlds"`
+	Routes     []*Route       `json:"routes"`
 }
 
 func (s *Sector) ToFileName() string {
 	return "sector-" + s.Name
 }
+
+// WorldAt returns the world at the given hex location, if there is one
+func (s *Sector) WorldAt(hex string) (*SectorWorld, bool) {
+	for _, w := range s.Worlds {
+		if w.WorldSummaryData.HexLocation == hex {
+			return w, true
+		}
+	}
+	return nil, false
+}
+
+// WorldsIn returns the worlds in the given subsector
+func (s *Sector) WorldsIn(subsector string) []*SectorWorld {
+	worlds := make([]*SectorWorld, 0)
+	for _, w := range s.Worlds {
+		if w.Subsector == subsector {
+			worlds = append(worlds, w)
+		}
+	}
+	return worlds
+}
+
+// SubsectorLetters returns the letters of all subsectors in a sector, A to P
+func SubsectorLetters() []string {
+	letters := make([]string, 0, len(subsectorLetters))
+	for _, l := range subsectorLetters {
+		letters = append(letters, string(l))
+	}
+	return letters
+}
+
+// HexID formats a column and row as a 4-digit hex location, e.g. 0304
+func HexID(col int, row int) string {
+	return fmt.Sprintf("%02d%02d", col, row)
+}
+
+// ParseHexID splits a 4-digit hex location into its column and row, checking it is within a sector
+func ParseHexID(hex string) (int, int, error) {
+	if len(hex) != 4 {
+		return 0, 0, fmt.Errorf("hex %s must be 4 digits, e.g. 0304", hex)
+	}
+	col, errCol := strconv.Atoi(hex[0:2])
+	row, errRow := strconv.Atoi(hex[2:4])
+	if errCol != nil || errRow != nil {
+		return 0, 0, fmt.Errorf("hex %s must be 4 digits, e.g. 0304", hex)
+	}
+	if col < 1 || col > hexgrid.SectorCols || row < 1 || row > hexgrid.SectorRows {
+		return 0, 0, fmt.Errorf("hex %s is outside the sector (0101 to %s)", hex, HexID(hexgrid.SectorCols, hexgrid.SectorRows))
+	}
+	return col, row, nil
+}
+
+// SubsectorForHex returns the letter of the subsector holding the given column and row
+func SubsectorForHex(col int, row int) string {
+	idx := ((row-1)/SubsectorRows)*(hexgrid.SectorCols/SubsectorCols) + (col-1)/SubsectorCols
+	return subsectorLetters[idx : idx+1]
+}
@@ -0,0 +1,253 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const (
+	// UnskilledDM is the DM for a check made by a crew with no one trained in the skill
+	UnskilledDM = -3
+
+	// a jump uses fuel equal to 10% of the ship's tonnage for each parsec jumped
+	jumpFuelPercentPerParsec = 10
+
+	basicPassengersPerStateroom = 2
+)
+
+// ShipOperatingCosts are the ship's running costs in credits per month, other than the crew's salaries
+type ShipOperatingCosts struct {
+	Mortgage    int `json:"mortgage"`
+	Maintenance int `json:"maintenance"`
+}
+
+// CrewMember is one of the ship's crew, with the skills and other facts that affect trade. Skill names are
+// matched ignoring case (e.g. 'steward', 'Broker')
+type CrewMember struct {
+	Name           string         `json:"name"`
+	Role           string         `json:"role,omitempty"`
+	Salary         int            `json:"salary,omitempty"`
+	ScoutNavalRank int            `json:"scout-naval-rank,omitempty"`
+	SocDM          int            `json:"soc-dm,omitempty"`
+	Skills         map[string]int `json:"skills,omitempty"`
+}
+
+// ShipProfile is the ship the party trades with and its crew. Every crew member takes one of the staterooms;
+// the rest are free for passengers
+type ShipProfile struct {
+	Name           string             `json:"name"`
+	Tonnage        int                `json:"tonnage"`
+	CargoTons      int                `json:"cargo-tons"`
+	Staterooms     int                `json:"staterooms"`
+	LowBerths      int                `json:"low-berths"`
+	JumpRating     int                `json:"jump-rating"`
+	MDrive         int                `json:"m-drive,omitempty"`
+	FuelTons       int                `json:"fuel-tons"`
+	IsArmed        bool               `json:"armed"`
+	OperatingCosts ShipOperatingCosts `json:"operating-costs"`
+	Crew           []*CrewMember      `json:"crew"`
+}
+
+func ShipProfileFromFile(b []byte) (*ShipProfile, error) {
+	var ship ShipProfile
+	err := json.Unmarshal(b, &ship)
+	if err != nil {
+		return nil, err
+	}
+	return &ship, nil
+}
+
+// ShipProfileFromCharacterData builds a ship from the ship facts and skills in the character data of a trade data
+// file, for files written before ship profiles. It has no tonnage, so its jump range is its jump rating
+func ShipProfileFromCharacterData(cd *CharacterDataType) *ShipProfile {
+	return &ShipProfile{
+		CargoTons:  cd.ShipCargoTons,
+		JumpRating: cd.ShipJumpRating,
+		IsArmed:    cd.ShipIsArmed,
+		Crew: []*CrewMember{{
+			Name:           "crew",
+			ScoutNavalRank: cd.HighestScoutNavalRank,
+			SocDM:          cd.HighestSocSkillDM,
+			Skills:         map[string]int{"steward": cd.HighestStewardLevel},
+		}},
+	}
+}
+
+func (s *ShipProfile) Validate() []error {
+	errs := make([]error, 0)
+
+	if s.Tonnage <= 0 {
+		errs = append(errs, fmt.Errorf("invalid ship tonnage: %d", s.Tonnage))
+	}
+	if s.CargoTons < 0 || s.CargoTons > s.Tonnage {
+		errs = append(errs, fmt.Errorf("invalid ship cargo tonnage: %d", s.CargoTons))
+	}
+	if s.Staterooms < 0 {
+		errs = append(errs, fmt.Errorf("invalid number of staterooms: %d", s.Staterooms))
+	}
+	if s.LowBerths < 0 {
+		errs = append(errs, fmt.Errorf("invalid number of low berths: %d", s.LowBerths))
+	}
+	if !(s.JumpRating >= 0 && s.JumpRating <= 9) {
+		errs = append(errs, fmt.Errorf("invalid ship jump rating: %d", s.JumpRating))
+	}
+	if !(s.MDrive >= 0 && s.MDrive <= 9) {
+		errs = append(errs, fmt.Errorf("invalid ship m-drive rating: %d", s.MDrive))
+	}
+	if s.FuelTons < 0 || s.FuelTons > s.Tonnage {
+		errs = append(errs, fmt.Errorf("invalid ship fuel tonnage: %d", s.FuelTons))
+	}
+	if s.OperatingCosts.Mortgage < 0 || s.OperatingCosts.Maintenance < 0 {
+		errs = append(errs, fmt.Errorf("ship operating costs cannot be negative"))
+	}
+
+	nameSet := make(map[string]struct{})
+	for _, c := range s.Crew {
+		if _, exists := nameSet[c.Name]; exists {
+			errs = append(errs, fmt.Errorf("duplicate crew member name detected: %s", c.Name))
+		}
+		nameSet[c.Name] = struct{}{}
+
+		if c.Salary < 0 {
+			errs = append(errs, fmt.Errorf("invalid salary for crew member %s: %d", c.Name, c.Salary))
+		}
+		if !(c.ScoutNavalRank >= 0 && c.ScoutNavalRank <= 6) {
+			errs = append(errs, fmt.Errorf("invalid scout or naval rank for crew member %s: %d", c.Name, c.ScoutNavalRank))
+		}
+		if !(c.SocDM >= -3 && c.SocDM <= 3) {
+			errs = append(errs, fmt.Errorf("invalid SOC DM for crew member %s: %d", c.Name, c.SocDM))
+		}
+		for skill, level := range c.Skills {
+			if !(level >= 0 && level <= 6) {
+				errs = append(errs, fmt.Errorf("invalid %s skill level for crew member %s: %d", skill, c.Name, level))
+			}
+		}
+	}
+
+	return errs
+}
+
+// IsFullProfile is false for a ship built from character data, which knows nothing of the ship's tonnage, fuel
+// or berths
+func (s *ShipProfile) IsFullProfile() bool {
+	return s.Tonnage > 0
+}
+
+// HighestSkill is the best level any crew member has in the skill, or 0 if no one has it. It is the DM where a
+// rule adds the highest skill on board, such as Steward for passenger traffic
+func (s *ShipProfile) HighestSkill(skill string) int {
+	highest, _ := s.bestSkill(skill)
+	return highest
+}
+
+// SkillCheckDM is the DM for a check made by the crew member best at the skill, or UnskilledDM if no one has it
+func (s *ShipProfile) SkillCheckDM(skill string) int {
+	highest, ok := s.bestSkill(skill)
+	if !ok {
+		return UnskilledDM
+	}
+	return highest
+}
+
+func (s *ShipProfile) bestSkill(skill string) (int, bool) {
+	highest, found := 0, false
+	for _, c := range s.Crew {
+		for name, level := range c.Skills {
+			if strings.EqualFold(name, skill) && (!found || level > highest) {
+				highest, found = level, true
+			}
+		}
+	}
+	return highest, found
+}
+
+// HighestScoutNavalRank is the best scout or naval rank held by any crew member
+func (s *ShipProfile) HighestScoutNavalRank() int {
+	highest := 0
+	for _, c := range s.Crew {
+		if c.ScoutNavalRank > highest {
+			highest = c.ScoutNavalRank
+		}
+	}
+	return highest
+}
+
+// HighestSocDM is the best SOC DM of any crew member
+func (s *ShipProfile) HighestSocDM() int {
+	if len(s.Crew) == 0 {
+		return 0
+	}
+	highest := s.Crew[0].SocDM
+	for _, c := range s.Crew {
+		if c.SocDM > highest {
+			highest = c.SocDM
+		}
+	}
+	return highest
+}
+
+// JumpFuelPerParsec is the tons of fuel used for each parsec jumped
+func (s *ShipProfile) JumpFuelPerParsec() int {
+	return (s.Tonnage*jumpFuelPercentPerParsec + 99) / 100
+}
+
+// JumpRange is the furthest the ship can jump at once: its jump rating, unless it cannot carry the fuel for a
+// jump that long. A ship that is not a full profile is not limited by fuel
+func (s *ShipProfile) JumpRange() int {
+	if !s.IsFullProfile() {
+		return s.JumpRating
+	}
+	return minInt(s.JumpRating, s.FuelParsecs())
+}
+
+// FuelParsecs is how many parsecs the ship can jump on full tanks, possibly over several jumps. A ship that is not a
+// full profile is taken to carry the fuel for one jump at its jump rating
+func (s *ShipProfile) FuelParsecs() int {
+	if !s.IsFullProfile() {
+		return s.JumpRating
+	}
+	return s.FuelTons / s.JumpFuelPerParsec()
+}
+
+// Thrust is the ship's acceleration in G from its m-drive rating. A ship with no rating given is taken to have
+// thrust 1
+func (s *ShipProfile) Thrust() int {
+	if s.MDrive <= 0 {
+		return 1
+	}
+	return s.MDrive
+}
+
+// PassengerStaterooms is the number of staterooms not taken by the crew
+func (s *ShipProfile) PassengerStaterooms() int {
+	free := s.Staterooms - len(s.Crew)
+	if free < 0 {
+		return 0
+	}
+	return free
+}
+
+// PassengerCapacity is how many passengers of each passage type can be carried from the numbers wanting passage.
+// High and middle passengers take a stateroom each and basic passengers share two to a stateroom, with the
+// staterooms going to the best paying passengers first. Low passengers each take a low berth
+func (s *ShipProfile) PassengerCapacity(wanting map[string]int) map[string]int {
+	carried := make(map[string]int)
+
+	staterooms := s.PassengerStaterooms()
+	for _, passage := range []string{"high", "middle"} {
+		carried[passage] = minInt(wanting[passage], staterooms)
+		staterooms -= carried[passage]
+	}
+	carried["basic"] = minInt(wanting["basic"], staterooms*basicPassengersPerStateroom)
+	carried["low"] = minInt(wanting["low"], s.LowBerths)
+
+	return carried
+}
+
+func minInt(i int, j int) int {
+	if i < j {
+		return i
+	}
+	return j
+}
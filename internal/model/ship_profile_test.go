@@ -0,0 +1,49 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShipProfile(t *testing.T) {
+
+	ship := &ShipProfile{
+		Tonnage:    200,
+		CargoTons:  80,
+		Staterooms: 6,
+		LowBerths:  4,
+		JumpRating: 2,
+		FuelTons:   40,
+		Crew: []*CrewMember{
+			{Name: "a", Skills: map[string]int{"Steward": 1}, SocDM: -2},
+			{Name: "b", Skills: map[string]int{"steward": 2, "broker": 0}, SocDM: -1},
+		},
+	}
+	assert.Empty(t, ship.Validate())
+	assert.True(t, ship.IsFullProfile())
+
+	assert.Equal(t, 2, ship.HighestSkill("steward"))
+	assert.Equal(t, 0, ship.HighestSkill("Broker"))
+	assert.Equal(t, 0, ship.HighestSkill("carouse"))
+	assert.Equal(t, 0, ship.SkillCheckDM("Broker"))
+	assert.Equal(t, UnskilledDM, ship.SkillCheckDM("carouse"))
+	assert.Equal(t, -1, ship.HighestSocDM())
+
+	assert.Equal(t, 20, ship.JumpFuelPerParsec())
+	assert.Equal(t, 2, ship.JumpRange())
+	ship.FuelTons = 30
+	assert.Equal(t, 1, ship.JumpRange())
+
+	carried := ship.PassengerCapacity(map[string]int{"high": 1, "middle": 2, "basic": 5, "low": 6})
+	assert.Equal(t, map[string]int{"high": 1, "middle": 2, "basic": 2, "low": 4}, carried)
+
+	ship.CargoTons = 300
+	ship.Crew = append(ship.Crew, &CrewMember{Name: "a"})
+	assert.Len(t, ship.Validate(), 2)
+
+	old := ShipProfileFromCharacterData(&CharacterDataType{HighestStewardLevel: 1, ShipJumpRating: 3})
+	assert.False(t, old.IsFullProfile())
+	assert.Equal(t, 3, old.JumpRange())
+	assert.Equal(t, 1, old.HighestSkill("steward"))
+}
@@ -0,0 +1,143 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"tas/internal/util"
+)
+
+const (
+	OrbitZoneInner     = "inner"
+	OrbitZoneHabitable = "habitable"
+	OrbitZoneOuter     = "outer"
+
+	OrbitContentsMainworld     = "mainworld"
+	OrbitContentsGasGiant      = "gas giant"
+	OrbitContentsPlanetoidBelt = "planetoid belt"
+	OrbitContentsEmpty         = "empty"
+
+	StarSizeWhiteDwarf = "D"
+)
+
+// Star is a single star e.g. G2 V. Companions also record their position relative to the primary
+type Star struct {
+	SpectralClass string `json:"spectral-class"`
+	Decimal       int    `json:"decimal"`
+	Size          string `json:"size"`
+	Position      string `json:"position,omitempty"`
+}
+
+func (s *Star) String() string {
+	if s.Size == StarSizeWhiteDwarf {
+		return StarSizeWhiteDwarf
+	}
+	return s.SpectralClass + strconv.Itoa(s.Decimal) + sp + s.Size
+}
+
+type SystemOrbit struct {
+	Number   int    `json:"number"`
+	Zone     string `json:"zone"`
+	Contents string `json:"contents"`
+}
+
+// StarSystem is the system around a mainworld: its stars, the number of belts and gas giants and where
+// everything orbits
+type StarSystem struct {
+	Primary              *Star          `json:"primary"`
+	Companions           []*Star        `json:"companions"`
+	PopulationMultiplier int            `json:"population-multiplier"`
+	PlanetoidBelts       int            `json:"planetoid-belts"`
+	GasGiants            int            `json:"gas-giants"`
+	HabitableZoneOrbit   int            `json:"habitable-zone-orbit"`
+	MainworldOrbit       int            `json:"mainworld-orbit"`
+	Orbits               []*SystemOrbit `json:"orbits"`
+}
+
+// PBG is the population multiplier, planetoid belt and gas giant code e.g. 503. An unpopulated world has
+// no population multiplier
+func (s *StarSystem) PBG(population int) string {
+	p := s.PopulationMultiplier
+	if population == 0 {
+		p = 0
+	}
+	return eHex(p) + eHex(s.PlanetoidBelts) + eHex(s.GasGiants)
+}
+
+// Stars lists all stars in the system, primary first, e.g. 'G2 V M4 V'
+func (s *StarSystem) Stars() string {
+	stars := []string{s.Primary.String()}
+	for _, c := range s.Companions {
+		stars = append(stars, c.String())
+	}
+	return strings.Join(stars, sp)
+}
+
+// HabitableZoneOffset is how many orbits the mainworld is beyond (positive) or inside (negative) the habitable zone
+func (s *StarSystem) HabitableZoneOffset() int {
+	return s.MainworldOrbit - s.HabitableZoneOrbit
+}
+
+var starSizes = map[string]struct{}{"Ia": {}, "Ib": {}, "II": {}, "III": {}, "IV": {}, "V": {}, "VI": {}}
+
+// ParseStarSystem rebuilds what is known of a system from published sector data: its PBG code (e.g. 503) and
+// stars (e.g. 'G2 V M4 V'). Orbits are not recorded in sector data so are left empty
+func ParseStarSystem(pbg string, stars string) (*StarSystem, error) {
+
+	if len(pbg) != 3 {
+		return nil, fmt.Errorf("PBG %s must be 3 digits, e.g. 503", pbg)
+	}
+	values := make([]int, 0, len(pbg))
+	for _, c := range pbg {
+		v, err := util.EHexAsInt(string(c))
+		if err != nil {
+			return nil, fmt.Errorf("PBG %s must be 3 digits, e.g. 503", pbg)
+		}
+		values = append(values, v)
+	}
+
+	parsed, err := ParseStars(stars)
+	if err != nil {
+		return nil, err
+	}
+
+	sys := &StarSystem{
+		Primary:              parsed[0],
+		Companions:           parsed[1:],
+		PopulationMultiplier: values[0],
+		PlanetoidBelts:       values[1],
+		GasGiants:            values[2],
+		Orbits:               make([]*SystemOrbit, 0),
+	}
+	return sys, nil
+}
+
+// ParseStars parses a list of stars such as 'G2 V M4 V D' (the reverse of Stars), primary first
+func ParseStars(stars string) ([]*Star, error) {
+
+	tokens := strings.Fields(stars)
+	parsed := make([]*Star, 0, len(tokens))
+	for i := 0; i < len(tokens); i++ {
+		t := tokens[i]
+		if t == StarSizeWhiteDwarf {
+			parsed = append(parsed, &Star{Size: StarSizeWhiteDwarf})
+			continue
+		}
+		if len(t) != 2 || !strings.Contains("OBAFGKM", t[0:1]) || t[1] < '0' || t[1] > '9' {
+			return nil, fmt.Errorf("star %s must be a spectral class and decimal, e.g. G2, or D for a white dwarf", t)
+		}
+		if i+1 >= len(tokens) {
+			return nil, fmt.Errorf("star %s has no size, e.g. V", t)
+		}
+		if _, ok := starSizes[tokens[i+1]]; !ok {
+			return nil, fmt.Errorf("star %s has an unknown size %s, must be one of Ia, Ib, II, III, IV, V or VI", t, tokens[i+1])
+		}
+		parsed = append(parsed, &Star{SpectralClass: t[0:1], Decimal: int(t[1] - '0'), Size: tokens[i+1]})
+		i++
+	}
+	if len(parsed) == 0 {
+		return nil, fmt.Errorf("no stars were given")
+	}
+	return parsed, nil
+}
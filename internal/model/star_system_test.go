@@ -0,0 +1,28 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseStarSystem(t *testing.T) {
+
+	sys, err := ParseStarSystem("503", "G2 V M4 V D")
+	assert.NoError(t, err)
+	assert.Equal(t, 5, sys.PopulationMultiplier)
+	assert.Equal(t, 0, sys.PlanetoidBelts)
+	assert.Equal(t, 3, sys.GasGiants)
+	assert.Len(t, sys.Companions, 2)
+	assert.Equal(t, "G2 V M4 V D", sys.Stars())
+	assert.Equal(t, "503", sys.PBG(7))
+
+	_, err = ParseStarSystem("50", "G2 V")
+	assert.Error(t, err)
+	_, err = ParseStarSystem("503", "G2")
+	assert.Error(t, err)
+	_, err = ParseStarSystem("503", "G2 VII")
+	assert.Error(t, err)
+	_, err = ParseStarSystem("503", "")
+	assert.Error(t, err)
+}
@@ -5,6 +5,8 @@ import (
 	"time"
 )
 
+// SpeculativeTradeLot is a lot of trade goods and its price DM. When prices are resolved, the roll on the Modified
+// Price table and the resulting prices are given too. When selling, the broker fee is per ton
 type SpeculativeTradeLot struct {
 	LotId        int    `json:"lot-id"`
 	Type         string `json:"type"`
@@ -12,12 +14,23 @@ type SpeculativeTradeLot struct {
 	TonsAvail    int    `json:"tons-avail"`
 	BasePrice    int    `json:"base-price"`
 	OfferPriceDM int    `json:"offer-price-dm"`
+	PriceRoll    int    `json:"price-roll,omitempty"`
+	PricePercent int    `json:"price-percent,omitempty"`
+	PricePerTon  int    `json:"price-per-ton,omitempty"`
+	LotPrice     int    `json:"lot-price,omitempty"`
+	BrokerFee    int    `json:"broker-fee,omitempty"`
 }
 
 type SpeculativeTradeSummary struct {
 	WorldName              string                 `json:"world"`
 	TransactionType        string                 `json:"transaction-type"`
 	FindSupplierOrBrokerDM int                    `json:"find-supplier-broker"`
+	SupplierSearchDays     int                    `json:"supplier-search-days"`
+	SupplierFoundDate      string                 `json:"supplier-found-date,omitempty"`
+	Seed                   int64                  `json:"seed"`
+	BrokerSkill            int                    `json:"broker-skill,omitempty"`
+	HiredBroker            bool                   `json:"hired-broker,omitempty"`
+	BrokerFeePercent       int                    `json:"broker-fee-percent,omitempty"`
 	TradeLots              []*SpeculativeTradeLot `json:"trade-lots"`
 	TradeNotes             []string               `json:"notes"`
 }
@@ -42,9 +55,23 @@ type PassengerDM struct {
 	Requirements string `json:"requirements"`
 }
 
+// PassengerTraffic is the number of passengers of one passage type found for a trip, how many of them the ship
+// has berths for, and what they pay
+type PassengerTraffic struct {
+	PassageType  string `json:"type"`
+	TrafficValue int    `json:"traffic-value"`
+	Passengers   int    `json:"passengers"`
+	Carried      int    `json:"carried"`
+	Fare         int    `json:"fare"`
+	Income       int    `json:"income"`
+}
+
 type PassengerTradeSummary struct {
-	PassengerDMs   []*PassengerDM `json:"dms"`
-	PassengerNotes []string       `json:"notes"`
+	PassengerDMs   []*PassengerDM      `json:"dms"`
+	PassengerNotes []string            `json:"notes"`
+	CheckEffect    int                 `json:"check-effect,omitempty"`
+	Traffic        []*PassengerTraffic `json:"traffic,omitempty"`
+	TotalIncome    int                 `json:"total-income,omitempty"`
 }
 
 type FreightDM struct {
@@ -52,9 +79,33 @@ type FreightDM struct {
 	DM      int    `json:"dm"`
 }
 
+// FreightTraffic is the number of lots of one lot type found for a trip
+type FreightTraffic struct {
+	LotType      string `json:"lot-type"`
+	TrafficValue int    `json:"traffic-value"`
+	Lots         int    `json:"lots"`
+}
+
+// FreightLot is a single lot of freight. Lots are all-or-nothing, so a lot is either carried whole or left behind
+type FreightLot struct {
+	LotType string `json:"lot-type"`
+	Tons    int    `json:"tons"`
+	Income  int    `json:"income"`
+	Carried bool   `json:"carried"`
+}
+
 type FreightTradeSummary struct {
-	FreightDMs   []*FreightDM `json:"dms"`
-	FreightNotes []string     `json:"notes"`
+	FreightDMs   []*FreightDM      `json:"dms"`
+	FreightNotes []string          `json:"notes"`
+	CheckEffect  int               `json:"check-effect,omitempty"`
+	Traffic      []*FreightTraffic `json:"traffic,omitempty"`
+	Lots         []*FreightLot     `json:"lots,omitempty"`
+	PricePerTon  int               `json:"price-per-ton,omitempty"`
+	CargoTons    int               `json:"cargo-tons,omitempty"`
+	CargoKnown   bool              `json:"cargo-known,omitempty"`
+	TonsCarried  int               `json:"tons-carried,omitempty"`
+	TotalIncome  int               `json:"total-income,omitempty"`
+	LatePenalty  string            `json:"late-penalty,omitempty"`
 }
 
 type MailTradeSummary struct {
@@ -66,6 +117,14 @@ type MailTradeSummary struct {
 type StandardTradeModifiers struct {
 	From           string                 `json:"from-world"`
 	To             string                 `json:"to-world"`
+	Seed           int64                  `json:"seed"`
+	Ship           string                 `json:"ship,omitempty"`
+	Parsecs        int                    `json:"parsecs,omitempty"`
+	JumpRating     int                    `json:"jump-rating,omitempty"`
+	JumpsRequired  int                    `json:"jumps-required,omitempty"`
+	Travel         *TravelTime            `json:"travel,omitempty"`
+	Departure      string                 `json:"departure-date,omitempty"`
+	Arrival        string                 `json:"arrival-date,omitempty"`
 	PassengerTrade *PassengerTradeSummary `json:"passenger-trade"`
 	FreightTrade   *FreightTradeSummary   `json:"freight-trade"`
 	MailTrade      *MailTradeSummary      `json:"mail-trade"`
@@ -0,0 +1,46 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// TripLineItem is one cost or one source of income for a trip, in credits
+type TripLineItem struct {
+	Item    string `json:"item"`
+	Credits int    `json:"credits"`
+	Note    string `json:"note,omitempty"`
+}
+
+// TripEconomics sets the costs of running the ship for a planned trip against the income from the passengers,
+// freight and mail carried on it
+type TripEconomics struct {
+	From          string          `json:"from-world"`
+	To            string          `json:"to-world"`
+	Ship          string          `json:"ship"`
+	Seed          int64           `json:"seed"`
+	Parsecs       int             `json:"parsecs"`
+	Jumps         int             `json:"jumps"`
+	MonthlyCosts  []*TripLineItem `json:"monthly-costs"`
+	TripCosts     []*TripLineItem `json:"trip-costs"`
+	Income        []*TripLineItem `json:"income"`
+	TotalCost     int             `json:"total-cost"`
+	TotalIncome   int             `json:"total-income"`
+	Profit        int             `json:"profit"`
+	CoversCosts   bool            `json:"covers-costs"`
+	EconomicNotes []string        `json:"notes"`
+}
+
+func (e *TripEconomics) ToFileName() string {
+	var sb strings.Builder
+
+	now := time.Now()
+
+	sb.WriteString("economics")
+	sb.WriteString(us + e.From)
+	sb.WriteString(us + e.To)
+	sb.WriteString(ds + now.Format("20060102150405"))
+	sb.WriteString(".json")
+
+	return sb.String()
+}
@@ -2,10 +2,12 @@ package model
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"regexp"
 	"strings"
 
+	"tas/internal/hexgrid"
 	"tas/internal/util"
 )
 
@@ -13,19 +15,39 @@ const (
 	basicUWPRegExString = "^[ABCDEX]{1}[0-9A]{1}[0-9A-F]{1}[0-9A]{1}[0-9A-C]{1}[0-9A-F]{1}[0-9]{1}-[0-9A-F]{1}"
 )
 
+var basicUWPPattern = regexp.MustCompile(basicUWPRegExString)
+
+// CharacterDataType holds the crew's skills and the ship facts that affect trade. A ship profile (see ShipProfile)
+// replaces it; it is only used for trade data files with no ship profile alongside
 type CharacterDataType struct {
 	HighestStewardLevel   int  `json:"highest-steward-skill"`
 	HighestScoutNavalRank int  `json:"highest-scout-naval-rank"`
 	HighestSocSkillDM     int  `json:"highest-soc-skill-dm"`
 	ShipIsArmed           bool `json:"ship-is-armed"`
+	ShipJumpRating        int  `json:"ship-jump-rating,omitempty"`
+	ShipCargoTons         int  `json:"ship-cargo-tons,omitempty"`
 }
 
-type WorldTradeInfoType struct {
+// SectorLocationType places a named sector relative to the other sectors in the trade data, so that
+// distances can be worked out between worlds in different sectors
+type SectorLocationType struct {
 	Name string `json:"name"`
-	UWP  string `json:"uwp"`
+	X    int    `json:"x"`
+	Y    int    `json:"y"`
+}
+
+// WorldTradeInfoType is a world as given in the trade data file. Hex and sector are optional; if the hex is
+// given the distance to other worlds with a hex is worked out. A world with no sector is in sector 0,0
+type WorldTradeInfoType struct {
+	Name   string `json:"name"`
+	UWP    string `json:"uwp"`
+	Hex    string `json:"hex,omitempty"`
+	Sector string `json:"sector,omitempty"`
 }
 
 type WorldTradeInfo struct {
+	Hex        *hexgrid.Hex
+	Size       int
 	Population int
 	Starport   string
 	ZoneAmber  bool
@@ -35,9 +57,11 @@ type WorldTradeInfo struct {
 }
 
 type TradeFacts struct {
-	CharacterData     *CharacterDataType    `json:"character-data"`
-	RawWorldTradeInfo []*WorldTradeInfoType `json:"world-data"`
-	WorldInfoMap      map[string]*WorldTradeInfo
+	CharacterData     *CharacterDataType         `json:"character-data"`
+	Sectors           []*SectorLocationType      `json:"sectors,omitempty"`
+	RawWorldTradeInfo []*WorldTradeInfoType      `json:"world-data"`
+	WorldInfoMap      map[string]*WorldTradeInfo `json:"-"`
+	Ship              *ShipProfile               `json:"-"`
 	isValidated       bool
 }
 
@@ -72,16 +96,45 @@ func (t *TradeFacts) Validate() []error {
 		errs = append(errs, fmt.Errorf("invalid SOC skill DM: %d", sdm))
 	}
 
+	jr := t.CharacterData.ShipJumpRating
+	if !(jr >= 0 && jr <= 9) {
+		errs = append(errs, fmt.Errorf("invalid ship jump rating: %d", jr))
+	}
+
+	if t.CharacterData.ShipCargoTons < 0 {
+		errs = append(errs, fmt.Errorf("invalid ship cargo tonnage: %d", t.CharacterData.ShipCargoTons))
+	}
+
+	//sector names must be unique so worlds can refer to them
+	sectorSet := make(map[string]struct{})
+	for _, s := range t.Sectors {
+		if _, exists := sectorSet[s.Name]; exists {
+			errs = append(errs, fmt.Errorf("duplicate sector name detected: %s", s.Name))
+		}
+		sectorSet[s.Name] = struct{}{}
+	}
+
 	//ensure that all world names are unique and UWP are valid
 	nameSet := make(map[string]struct{})
 
-	basicPattern := regexp.MustCompile(basicUWPRegExString)
-
 	for _, w := range t.RawWorldTradeInfo {
 		nameSet[w.Name] = struct{}{}
-		if !basicPattern.MatchString(w.UWP) {
+		if !ValidTradeUWP(w.UWP) {
 			errs = append(errs, fmt.Errorf("world: %s has invalid basic UWP: %s", w.Name, w.UWP))
 		}
+		if w.Hex != "" {
+			if _, err := hexgrid.Parse(w.Hex); err != nil {
+				errs = append(errs, fmt.Errorf("world: %s has invalid hex: %w", w.Name, err))
+			}
+		}
+		if w.Sector != "" {
+			if _, ok := sectorSet[w.Sector]; !ok {
+				errs = append(errs, fmt.Errorf("world: %s is in sector: %s, which is not in the sectors list", w.Name, w.Sector))
+			}
+			if w.Hex == "" {
+				errs = append(errs, fmt.Errorf("world: %s has a sector but no hex", w.Name))
+			}
+		}
 	}
 
 	if len(nameSet) != len(t.RawWorldTradeInfo) {
@@ -95,8 +148,9 @@ func (t *TradeFacts) Validate() []error {
 	return errs
 }
 
-// Parse returns true if []errors contains an actual error or false if []errors is nil or contains warnings we want to wrap like errors
-func (t *TradeFacts) Parse() (bool, []error) {
+// Parse returns true if []errors contains an actual error or false if []errors is nil or contains warnings we want to wrap like errors.
+// Trade codes are recognised from the trade code catalogue, ignoring case
+func (t *TradeFacts) Parse(tradeCodes WorldTradeCodeMap) (bool, []error) {
 
 	errs := make([]error, 0)
 
@@ -113,9 +167,19 @@ func (t *TradeFacts) Parse() (bool, []error) {
 			TradeCodes: map[string]struct{}{},
 		}
 
+		//hex location is optional, but was validated if given
+		if raw.Hex != "" {
+			hex, _ := hexgrid.Parse(raw.Hex, t.SectorOffset(raw.Sector))
+			wi.Hex = &hex
+		}
+
 		//starport is always the first value
 		wi.Starport = string(raw.UWP[0])
 
+		//size is always the char at index 1 in the basic UWP
+		size, _ := util.HexAsInt(string(raw.UWP[1])) //no error check here as regex validated this
+		wi.Size = size
+
 		//population is always the char at index 4 in the basic UWP
 		popString := raw.UWP[4]
 		pop, _ := util.HexAsInt(string(popString)) //no error check here as regex validated this
@@ -181,11 +245,11 @@ func (t *TradeFacts) Parse() (bool, []error) {
 
 			case 2: //this is the most interesting as it could be a pair of base codes or an expected 2-letter trade code
 
-				if _, ok := util.ValidTradeCodeSet[thisElement]; ok { //we recognize this pair as a trade code
-					if _, exists := wi.TradeCodes[thisElement]; exists {
+				if tc, ok := tradeCodes.ByCode(thisElement); ok { //we recognize this pair as a trade code
+					if _, exists := wi.TradeCodes[tc.Code]; exists {
 						errs = append(errs, fmt.Errorf("world: %s has redundant trade code: %s", raw.Name, thisElement))
 					}
-					wi.TradeCodes[thisElement] = struct{}{}
+					wi.TradeCodes[tc.Code] = struct{}{}
 				} else { //the pair is not a trade code, we will assume it is a base code
 					errs = append(errs, fmt.Errorf("world: %s has UWP with entry '%s', which we are treating as a Base designation", raw.Name, thisElement))
 				}
@@ -202,6 +266,67 @@ func (t *TradeFacts) Parse() (bool, []error) {
 	return false, errs
 }
 
+// ValidTradeUWP is true if the UWP starts with a basic profile (e.g. CA6A643-9) within the ranges the trade rules use
+func ValidTradeUWP(uwp string) bool {
+	return basicUWPPattern.MatchString(uwp)
+}
+
+// ReplaceSectorWorlds swaps the worlds of the named sector for the given worlds, keeping the worlds of every other
+// sector. If only part of the sector was generated, covered is true for the hexes that were, and the sector's worlds
+// in other hexes are kept too; a nil covered replaces the whole sector. The sector is added to the sectors list if
+// it is not already there
+func (t *TradeFacts) ReplaceSectorWorlds(sector string, worlds []*WorldTradeInfoType, covered func(hex string) bool) {
+
+	kept := make([]*WorldTradeInfoType, 0, len(t.RawWorldTradeInfo)+len(worlds))
+	for _, w := range t.RawWorldTradeInfo {
+		if w.Sector != sector || (covered != nil && !covered(w.Hex)) {
+			kept = append(kept, w)
+		}
+	}
+	t.RawWorldTradeInfo = append(kept, worlds...)
+	t.isValidated = false
+
+	for _, s := range t.Sectors {
+		if s.Name == sector {
+			return
+		}
+	}
+	t.Sectors = append(t.Sectors, &SectorLocationType{Name: sector})
+}
+
+// SectorOffset returns the location of the named sector. Worlds with no sector are in sector 0,0
+func (t *TradeFacts) SectorOffset(name string) hexgrid.SectorOffset {
+	for _, s := range t.Sectors {
+		if s.Name == name {
+			return hexgrid.SectorOffset{X: s.X, Y: s.Y}
+		}
+	}
+	return hexgrid.SectorOffset{}
+}
+
+// ErrCannotJump is returned when the ship is needed to jump, but has no jump drive or can't carry the fuel to jump
+var ErrCannotJump = errors.New("the ship can't jump - it has no jump drive, or can't carry the fuel for a jump")
+
+// ShipProfile is the ship trade is done with: the ship profile if there is one, otherwise a ship built from the
+// character data
+func (t *TradeFacts) ShipProfile() *ShipProfile {
+	if t.Ship != nil {
+		return t.Ship
+	}
+	return ShipProfileFromCharacterData(t.CharacterData)
+}
+
+// JumpRating is the furthest the ship can jump at once (see ShipProfile.JumpRange). A ship built from character data
+// with no rating given is assumed to be jump-1. It is 0 for a ship profile that can't jump at all, either for want
+// of a jump drive or of the fuel for a jump (see ErrCannotJump)
+func (t *TradeFacts) JumpRating() int {
+	ship := t.ShipProfile()
+	if !ship.IsFullProfile() && ship.JumpRating < 1 {
+		return 1
+	}
+	return ship.JumpRange()
+}
+
 func (t *TradeFacts) DataForWorldName(name string) (*WorldTradeInfo, bool) {
 	data := t.WorldInfoMap[name]
 	if data == nil {
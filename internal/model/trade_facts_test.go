@@ -0,0 +1,58 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplaceSectorWorlds(t *testing.T) {
+
+	newFacts := func() *TradeFacts {
+		return &TradeFacts{
+			Sectors: []*SectorLocationType{{Name: "Spinward"}},
+			RawWorldTradeInfo: []*WorldTradeInfoType{
+				{Name: "Regina", Hex: "0101", Sector: "Spinward"},
+				{Name: "Efate", Hex: "0102", Sector: "Spinward"},
+				{Name: "Jewell", Hex: "0101", Sector: "Trojan"},
+			},
+		}
+	}
+	names := func(f *TradeFacts) []string {
+		n := make([]string, 0)
+		for _, w := range f.RawWorldTradeInfo {
+			n = append(n, w.Name)
+		}
+		return n
+	}
+	fresh := []*WorldTradeInfoType{{Name: "Rhylanor", Hex: "0101", Sector: "Spinward"}}
+
+	//the whole sector is replaced
+	f := newFacts()
+	f.ReplaceSectorWorlds("Spinward", fresh, nil)
+	assert.Equal(t, []string{"Jewell", "Rhylanor"}, names(f))
+
+	//only the covered hexes are replaced
+	f = newFacts()
+	f.ReplaceSectorWorlds("Spinward", fresh, func(hex string) bool { return hex == "0101" })
+	assert.Equal(t, []string{"Efate", "Jewell", "Rhylanor"}, names(f))
+
+	//a new sector is listed
+	f = newFacts()
+	f.ReplaceSectorWorlds("Deneb", nil, nil)
+	assert.Len(t, f.Sectors, 2)
+}
+
+func TestTradeFactsJumpRating(t *testing.T) {
+
+	//a ship built from character data with no rating is jump-1
+	facts := &TradeFacts{CharacterData: &CharacterDataType{}}
+	assert.Equal(t, 1, facts.JumpRating())
+
+	//a ship profile is limited by its fuel, and can't jump without any
+	ship := &ShipProfile{Tonnage: 200, JumpRating: 2, FuelTons: 20}
+	facts.Ship = ship
+	assert.Equal(t, 1, facts.JumpRating())
+	ship.FuelTons = 0
+	assert.Equal(t, 0, facts.JumpRating())
+}
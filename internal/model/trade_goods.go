@@ -2,6 +2,14 @@ package model
 
 import (
 	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+const (
+	TradeGoodAvailableEverywhere = "all"
+	TradeDMAmberZone             = "Amber Zone"
+	TradeDMRedZone               = "Red Zone"
 )
 
 type TradeGoodsMap map[int]*TradeGood
@@ -52,3 +60,44 @@ func TradeGoodsFromFile(b []byte) (TradeGoodsMap, error) {
 	}
 	return dataMap, nil
 }
+
+// UseCatalogueCodes checks every trade code in the goods' availability and DMs against the trade code catalogue
+// and writes them as the catalogue does, so they match the trade codes of worlds. Travel zones are kept as they are
+func (m TradeGoodsMap) UseCatalogueCodes(tradeCodes WorldTradeCodeMap) []error {
+
+	errs := make([]error, 0)
+	catalogueCode := func(good *TradeGood, code string) string {
+		tc, ok := tradeCodes.ByCode(code)
+		if !ok {
+			errs = append(errs, fmt.Errorf("trade good: %s uses unknown trade code: %s", good.Type, code))
+			return code
+		}
+		return tc.Code
+	}
+
+	for _, v := range m.Values() {
+		good := m[v]
+		for i, a := range good.Availability {
+			if a != TradeGoodAvailableEverywhere {
+				good.Availability[i] = catalogueCode(good, a)
+			}
+		}
+		for _, dm := range append(append([]*TradeDM{}, good.PurchaseDMs...), good.SaleDMs...) {
+			if dm.Code != TradeDMAmberZone && dm.Code != TradeDMRedZone {
+				dm.Code = catalogueCode(good, dm.Code)
+			}
+		}
+	}
+	return errs
+}
+
+// Values returns the map's keys (the D66 value of each trade good) in ascending order so that
+// callers rolling dice against the map always roll in the same order for a given seed
+func (m TradeGoodsMap) Values() []int {
+	values := make([]int, 0, len(m))
+	for v := range m {
+		values = append(values, v)
+	}
+	sort.Ints(values)
+	return values
+}
@@ -0,0 +1,78 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// TradePlanPair is the expected profit from buying a lot of goods at the current world and selling it at one
+// destination, from many simulated purchase and sale price rolls. Prices and profits are in credits per ton, and
+// LossPercent is how often a trial lost money
+type TradePlanPair struct {
+	LotId          int    `json:"lot-id"`
+	Goods          string `json:"goods"`
+	Destination    string `json:"destination"`
+	Parsecs        int    `json:"parsecs"`
+	TonsAvail      int    `json:"tons-available"`
+	BasePrice      int    `json:"base-price"`
+	PurchaseDM     int    `json:"purchase-dm"`
+	SaleDM         int    `json:"sale-dm"`
+	MeanPurchase   int    `json:"mean-purchase-price"`
+	MeanSale       int    `json:"mean-sale-price"`
+	ExpectedProfit int    `json:"expected-profit"`
+	ProfitVariance int    `json:"profit-variance"`
+	ProfitStdDev   int    `json:"profit-std-dev"`
+	LossPercent    int    `json:"loss-percent"`
+}
+
+// TradePlanCargo is the part of a lot bought for a destination, with its expected cost and profit
+type TradePlanCargo struct {
+	LotId          int    `json:"lot-id"`
+	Goods          string `json:"goods"`
+	Tons           int    `json:"tons"`
+	Cost           int    `json:"cost"`
+	ExpectedProfit int    `json:"expected-profit"`
+}
+
+// TradePlanLoad is the most profitable cargo to take to one destination that fits the hold and the budget
+type TradePlanLoad struct {
+	Destination    string            `json:"destination"`
+	Parsecs        int               `json:"parsecs"`
+	Cargo          []*TradePlanCargo `json:"cargo"`
+	Tons           int               `json:"tons"`
+	Cost           int               `json:"cost"`
+	ExpectedProfit int               `json:"expected-profit"`
+}
+
+// TradePlan weighs the goods for sale at the current world against the worlds within jump range to sell them at.
+// Pairs and loads are in order of expected profit, best first. The cargo hold is only a limit if CargoLimited is
+// set (a limited hold of 0 is full), and the budget only if BudgetLimited is set
+type TradePlan struct {
+	World            string           `json:"world"`
+	Seed             int64            `json:"seed"`
+	Trials           int              `json:"trials"`
+	JumpRange        int              `json:"jump-range"`
+	BrokerSkill      int              `json:"broker-skill"`
+	HiredBroker      bool             `json:"hired-broker,omitempty"`
+	BrokerFeePercent int              `json:"broker-fee-percent,omitempty"`
+	CargoTons        int              `json:"cargo-tons"`
+	CargoLimited     bool             `json:"cargo-limited"`
+	Budget           int              `json:"budget"`
+	BudgetLimited    bool             `json:"budget-limited"`
+	Pairs            []*TradePlanPair `json:"pairs"`
+	Loads            []*TradePlanLoad `json:"loads"`
+	Notes            []string         `json:"notes"`
+}
+
+func (p *TradePlan) ToFileName() string {
+	var sb strings.Builder
+
+	now := time.Now()
+
+	sb.WriteString("tradeplan")
+	sb.WriteString(us + p.World)
+	sb.WriteString(ds + now.Format("20060102150405"))
+	sb.WriteString(".json")
+
+	return sb.String()
+}
@@ -0,0 +1,61 @@
+package model
+
+import (
+	"math"
+)
+
+const (
+	// a jump takes about a week: 148 + 6D hours
+	JumpBaseHours    = 148
+	JumpVarianceDice = 6
+
+	// AverageJumpHours is the average time in jump, used when planning a route
+	AverageJumpHours = JumpBaseHours + JumpVarianceDice*7/2
+
+	// ships travel out to 100 diameters from a world before jumping and arrive at that distance from the
+	// destination
+	jumpLimitDiameters = 100
+
+	metresPerSecondPerG = 10
+	secondsPerHour      = 3600
+)
+
+// TravelTime is how long a trip takes: moving out from the source world to its jump limit, each jump, and
+// moving in from the destination's jump limit, all in hours
+type TravelTime struct {
+	Thrust         int   `json:"thrust"`
+	DepartureHours int   `json:"departure-hours"`
+	JumpHours      []int `json:"jump-hours"`
+	ArrivalHours   int   `json:"arrival-hours"`
+	TotalHours     int   `json:"total-hours"`
+}
+
+func NewTravelTime(thrust int, departureHours int, jumpHours []int, arrivalHours int) *TravelTime {
+	t := &TravelTime{
+		Thrust:         thrust,
+		DepartureHours: departureHours,
+		JumpHours:      jumpHours,
+		ArrivalHours:   arrivalHours,
+		TotalHours:     departureHours + arrivalHours,
+	}
+	for _, j := range jumpHours {
+		t.TotalHours += j
+	}
+	return t
+}
+
+// Days is the number of days the trip takes, with any part of a day counting as a whole day
+func (t *TravelTime) Days() int {
+	return HoursAsDays(t.TotalHours)
+}
+
+// JumpLimitHours is the time to travel between a world and its 100 diameter limit at the given thrust in G,
+// accelerating for the first half of the distance and decelerating for the rest (T = 2√(D/A))
+func JumpLimitHours(diameterKm int, thrust int) int {
+	if diameterKm <= 0 || thrust <= 0 {
+		return 0
+	}
+	metres := float64(diameterKm) * 1000 * jumpLimitDiameters
+	seconds := 2 * math.Sqrt(metres/float64(thrust*metresPerSecondPerG))
+	return int(math.Ceil(seconds / secondsPerHour))
+}
@@ -0,0 +1,18 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJumpLimitHours(t *testing.T) {
+	//an earth sized world is about 6 hours from its jump limit at 1G
+	assert.Equal(t, 7, JumpLimitHours(12800, 1))
+	assert.Equal(t, 5, JumpLimitHours(12800, 2))
+	assert.Equal(t, 0, JumpLimitHours(0, 1))
+
+	tt := NewTravelTime(1, 7, []int{160, 170}, 5)
+	assert.Equal(t, 342, tt.TotalHours)
+	assert.Equal(t, 15, tt.Days())
+}
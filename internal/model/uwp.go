@@ -0,0 +1,253 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"tas/internal/util"
+)
+
+const (
+	uwpCoreRegExString = "^[A-Z][0-9A-Z]{6}-[0-9A-Z]$"
+	hexRegExString     = "^[0-9]{4}$"
+
+	zoneGreen = "green"
+	zoneAmber = "amber"
+	zoneRed   = "red"
+)
+
+// UWPFieldError describes a problem with a single field of a UWP
+type UWPFieldError struct {
+	Field  string
+	Value  string
+	Reason string
+}
+
+func (e *UWPFieldError) Error() string {
+	return fmt.Sprintf("UWP %s has invalid value '%s': %s", e.Field, e.Value, e.Reason)
+}
+
+type uwpField struct {
+	name string
+	max  int
+	set  func(def *WorldDefinition, v int)
+	get  func(def *WorldDefinition) int
+}
+
+// the fields of the UWP after the starport, in order. The max values are the largest allowed in the UWP, not the largest generated
+var uwpFields = []uwpField{
+	{name: "size", max: 10, set: func(d *WorldDefinition, v int) { d.Size = v }, get: func(d *WorldDefinition) int { return d.Size }},
+	{name: "atmosphere", max: 15, set: func(d *WorldDefinition, v int) { d.Atmosphere = v }, get: func(d *WorldDefinition) int { return d.Atmosphere }},
+	{name: "hydrographics", max: 10, set: func(d *WorldDefinition, v int) { d.Hydrographics = v }, get: func(d *WorldDefinition) int { return d.Hydrographics }},
+	{name: "population", max: 15, set: func(d *WorldDefinition, v int) { d.Population = v }, get: func(d *WorldDefinition) int { return d.Population }},
+	{name: "government", max: 15, set: func(d *WorldDefinition, v int) { d.Government = v }, get: func(d *WorldDefinition) int { return d.Government }},
+	{name: "law level", max: 18, set: func(d *WorldDefinition, v int) { d.LawLevel = v }, get: func(d *WorldDefinition) int { return d.LawLevel }},
+}
+
+var uwpTechLevelField = uwpField{name: "tech level", max: 18, set: func(d *WorldDefinition, v int) { d.TechLevel = v }, get: func(d *WorldDefinition) int { return d.TechLevel }}
+
+// the starport value used when parsing is the lowest value on the starport table (pg 257) giving that class
+var starportValuesByCode = map[string]int{"A": 11, "B": 9, "C": 7, "D": 5, "E": 3, "X": 2}
+
+var baseNamesByCode = map[string]string{"N": "naval", "S": "scout", "M": "military", "C": "corsair", "D": "depot", "W": "way station"}
+
+// the older SEC format has a single column for bases, so some letters stand for a pair of bases
+var legacyBaseCodes = map[string]string{"A": "NS", "B": "NW", "F": "MN"}
+
+var zonesByCode = map[string]string{"G": zoneGreen, "A": zoneAmber, "R": zoneRed}
+
+// StarportCode returns the class letter (A-E, X) of a starport value from the starport table
+func StarportCode(value int) string {
+	code := "X"
+	for c, v := range starportValuesByCode {
+		if value >= v && v > starportValuesByCode[code] {
+			code = c
+		}
+	}
+	return code
+}
+
+// ParseUWP parses a Universal World Profile such as 'Corgi 0101 CA6A643-9 N RI WA A' into a WorldDefinition.
+// Only the core 'CA6A643-9' is required. T5 extensions such as '{ 2 } (A46+2) [1716]' may follow the remarks. Any problems with the UWP are reported field by field
+func ParseUWP(uwp string, tradeCodes WorldTradeCodeMap) (*WorldDefinition, []error) {
+
+	errs := make([]error, 0)
+	tokens := strings.Fields(importanceSpacingRegEx.ReplaceAllString(uwp, "{$1}"))
+
+	//find the core profile - everything before it is name and hex, everything after is bases, trade codes and zone
+	corePattern := regexp.MustCompile(uwpCoreRegExString)
+	coreIdx := -1
+	for i, t := range tokens {
+		if corePattern.MatchString(strings.ToUpper(t)) {
+			coreIdx = i
+			break
+		}
+	}
+	if coreIdx < 0 {
+		errs = append(errs, &UWPFieldError{Field: "profile", Value: uwp, Reason: "no profile of the form CA6A643-9 was found"})
+		return nil, errs
+	}
+
+	def := &WorldDefinition{
+		Starport:   &WorldStarportInfo{},
+		Bases:      make([]string, 0),
+		TradeCodes: make([]string, 0),
+		TravelZone: zoneGreen,
+	}
+
+	//name and hex location
+	nameTokens := tokens[:coreIdx]
+	if len(nameTokens) > 0 && regexp.MustCompile(hexRegExString).MatchString(nameTokens[len(nameTokens)-1]) {
+		def.SubsectorLoc = nameTokens[len(nameTokens)-1]
+		nameTokens = nameTokens[:len(nameTokens)-1]
+	}
+	def.Name = strings.Join(nameTokens, " ")
+
+	//core profile
+	core := strings.ToUpper(tokens[coreIdx])
+	starportCode := core[0:1]
+	if v, ok := starportValuesByCode[starportCode]; ok {
+		def.Starport.Value = v
+	} else {
+		errs = append(errs, &UWPFieldError{Field: "starport", Value: starportCode, Reason: "must be one of A, B, C, D, E or X"})
+	}
+
+	for i, f := range uwpFields {
+		errs = parseUWPField(def, f, core[i+1:i+2], errs)
+	}
+	errs = parseUWPField(def, uwpTechLevelField, core[8:9], errs)
+
+	//bases, trade codes, travel zone and any T5 extensions
+	haveBases := false
+	haveZone := false
+	ext := &WorldExtensions{}
+	haveExt := false
+	for _, r := range tokens[coreIdx+1:] {
+
+		if zone, ok := zonesByCode[strings.ToUpper(r)]; ok && !haveZone {
+			haveZone = true
+			def.TravelZone = zone
+			continue
+		}
+
+		var isExt bool
+		if isExt, errs = parseExtension(ext, r, errs); isExt {
+			haveExt = true
+			continue
+		}
+
+		if tc, ok := tradeCodes.ByCode(r); ok {
+			for _, existing := range def.TradeCodes {
+				if existing == tc.Code {
+					errs = append(errs, &UWPFieldError{Field: "trade codes", Value: r, Reason: "trade code is listed more than once"})
+				}
+			}
+			def.TradeCodes = append(def.TradeCodes, tc.Code)
+			continue
+		}
+
+		if bases, ok := parseBases(r); ok && !haveBases {
+			haveBases = true
+			def.Bases = bases
+			continue
+		}
+
+		errs = append(errs, &UWPFieldError{Field: "remarks", Value: r, Reason: "not a known base code, trade code or travel zone"})
+	}
+
+	if haveExt {
+		def.Extensions = ext
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return def, errs
+}
+
+func parseUWPField(def *WorldDefinition, f uwpField, s string, errs []error) []error {
+	v, err := util.EHexAsInt(s)
+	if err != nil || v > f.max {
+		maxString, _ := util.IntAsEHexString(f.max)
+		return append(errs, &UWPFieldError{Field: f.name, Value: s, Reason: "must be between 0 and " + maxString})
+	}
+	f.set(def, v)
+	return errs
+}
+
+// ParseBaseCodes parses the bases column of sector data, e.g. NS, including the combined letters of the older SEC format
+func ParseBaseCodes(code string) ([]string, error) {
+	if code == "" || code == "-" {
+		return make([]string, 0), nil
+	}
+	if legacy, ok := legacyBaseCodes[strings.ToUpper(code)]; ok {
+		code = legacy
+	}
+	bases, ok := parseBases(code)
+	if !ok {
+		return nil, &UWPFieldError{Field: "bases", Value: code, Reason: "not a known base code"}
+	}
+	return bases, nil
+}
+
+func parseBases(s string) ([]string, bool) {
+	bases := make([]string, 0, len(s))
+	seen := make(map[string]struct{})
+	for _, c := range strings.ToUpper(s) {
+		name, ok := baseNamesByCode[string(c)]
+		if !ok {
+			return nil, false
+		}
+		if _, dupe := seen[name]; dupe {
+			return nil, false
+		}
+		seen[name] = struct{}{}
+		bases = append(bases, name)
+	}
+	return bases, true
+}
+
+// ToUWP writes the world definition as a Universal World Profile, the reverse of ParseUWP. The T5
+// extensions are only included if asked for (and the world has them)
+func (def *WorldDefinition) ToUWP(tradeCodes WorldTradeCodeMap, withExtensions bool) string {
+	var uwp strings.Builder
+
+	if def.Name != "" {
+		uwp.WriteString(def.Name + sp)
+	}
+	if def.SubsectorLoc != "" {
+		uwp.WriteString(def.SubsectorLoc + sp)
+	}
+
+	uwp.WriteString(StarportCode(def.Starport.Value))
+	for _, f := range uwpFields {
+		digit, _ := util.IntAsEHexString(f.get(def))
+		uwp.WriteString(digit)
+	}
+	tech, _ := util.IntAsEHexString(def.TechLevel)
+	uwp.WriteString(ds + tech)
+
+	if len(def.Bases) > 0 {
+		uwp.WriteString(sp)
+		for _, b := range def.Bases {
+			uwp.WriteString(strings.ToUpper(b[0:1]))
+		}
+	}
+
+	for _, c := range def.TradeCodes {
+		if tc, ok := tradeCodes.ByCode(c); ok {
+			uwp.WriteString(sp + strings.ToUpper(tc.Code))
+		}
+	}
+
+	if def.TravelZone != "" && def.TravelZone != zoneGreen {
+		uwp.WriteString(sp + strings.ToUpper(def.TravelZone[0:1]))
+	}
+
+	if withExtensions && def.Extensions != nil {
+		uwp.WriteString(sp + def.Extensions.String())
+	}
+
+	return uwp.String()
+}
@@ -0,0 +1,102 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"tas/internal/util"
+)
+
+const (
+	importanceRegExString = `^\{\s*([+-]?\d+)\s*\}$`
+	economicRegExString   = `^\(([0-9A-Z])([0-9A-Z])([0-9A-Z])([+-]\d)\)$`
+	culturalRegExString   = `^\[([0-9A-Z])([0-9A-Z])([0-9A-Z])([0-9A-Z])\]$`
+)
+
+var (
+	importanceRegEx = regexp.MustCompile(importanceRegExString)
+	economicRegEx   = regexp.MustCompile(economicRegExString)
+	culturalRegEx   = regexp.MustCompile(culturalRegExString)
+
+	//the importance extension is often written with spaces inside the braces - e.g. { +2 }
+	importanceSpacingRegEx = regexp.MustCompile(`\{\s*([+-]?\d+)\s*\}`)
+)
+
+// ImportanceString formats the Importance extension as it appears in sector data e.g. { 2 } or { -1 }
+func (e *WorldExtensions) ImportanceString() string {
+	return fmt.Sprintf("{ %d }", e.Importance)
+}
+
+// EconomicString formats the Economic extension as it appears in sector data e.g. (A46+2)
+func (e *WorldExtensions) EconomicString() string {
+	if e.Economic == nil {
+		return ""
+	}
+	return "(" + eHex(e.Economic.Resources) + eHex(e.Economic.Labor) + eHex(e.Economic.Infrastructure) + fmt.Sprintf("%+d", e.Economic.Efficiency) + ")"
+}
+
+// CulturalString formats the Cultural extension as it appears in sector data e.g. [1716]
+func (e *WorldExtensions) CulturalString() string {
+	if e.Cultural == nil {
+		return ""
+	}
+	return "[" + eHex(e.Cultural.Heterogeneity) + eHex(e.Cultural.Acceptance) + eHex(e.Cultural.Strangeness) + eHex(e.Cultural.Symbols) + "]"
+}
+
+// String formats all the extensions, separated by spaces
+func (e *WorldExtensions) String() string {
+	s := e.ImportanceString()
+	if e.Economic != nil {
+		s += sp + e.EconomicString()
+	}
+	if e.Cultural != nil {
+		s += sp + e.CulturalString()
+	}
+	return s
+}
+
+// parseExtension parses a single {Ix}, (Ex) or [Cx] token into the extensions, returning false if the token
+// is not an extension at all
+func parseExtension(ext *WorldExtensions, token string, errs []error) (bool, []error) {
+
+	if m := importanceRegEx.FindStringSubmatch(token); m != nil {
+		ext.Importance, _ = strconv.Atoi(m[1])
+		return true, errs
+	}
+
+	if m := economicRegEx.FindStringSubmatch(token); m != nil {
+		values, errs := parseExtensionDigits("economic extension", m[1:4], errs)
+		efficiency, _ := strconv.Atoi(m[4])
+		ext.Economic = &WorldEconomics{Resources: values[0], Labor: values[1], Infrastructure: values[2], Efficiency: efficiency}
+		return true, errs
+	}
+
+	if m := culturalRegEx.FindStringSubmatch(token); m != nil {
+		values, errs := parseExtensionDigits("cultural extension", m[1:5], errs)
+		ext.Cultural = &WorldCulturalExtension{Heterogeneity: values[0], Acceptance: values[1], Strangeness: values[2], Symbols: values[3]}
+		return true, errs
+	}
+
+	return false, errs
+}
+
+func parseExtensionDigits(field string, digits []string, errs []error) ([]int, []error) {
+	values := make([]int, len(digits))
+	for i, d := range digits {
+		v, err := util.EHexAsInt(d)
+		if err != nil {
+			errs = append(errs, &UWPFieldError{Field: field, Value: d, Reason: err.Error()})
+		}
+		values[i] = v
+	}
+	return values, errs
+}
+
+func eHex(i int) string {
+	s, err := util.IntAsEHexString(i)
+	if err != nil {
+		return "?"
+	}
+	return s
+}
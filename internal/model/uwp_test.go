@@ -0,0 +1,71 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testTradeCodes = WorldTradeCodeMap{
+	"Ri": {Code: "Ri", Name: "rich"},
+	"Wa": {Code: "Wa", Name: "waterworld"},
+}
+
+func TestParseUWPRoundTrip(t *testing.T) {
+
+	uwp := "Corgi 0101 CA6A643-9 NS RI WA A"
+	def, errs := ParseUWP(uwp, testTradeCodes)
+	assert.Empty(t, errs)
+
+	assert.Equal(t, "Corgi", def.Name)
+	assert.Equal(t, "0101", def.SubsectorLoc)
+	assert.Equal(t, 10, def.Size)
+	assert.Equal(t, 9, def.TechLevel)
+	assert.Equal(t, []string{"naval", "scout"}, def.Bases)
+	assert.Equal(t, []string{"Ri", "Wa"}, def.TradeCodes)
+	assert.Equal(t, "amber", def.TravelZone)
+
+	assert.Equal(t, uwp, def.ToUWP(testTradeCodes, false))
+}
+
+func TestParseUWPFieldErrors(t *testing.T) {
+
+	_, errs := ParseUWP("QAGA6Z3-9 XX", testTradeCodes)
+	fields := make([]string, 0, len(errs))
+	for _, e := range errs {
+		fields = append(fields, e.(*UWPFieldError).Field)
+	}
+	assert.Equal(t, []string{"starport", "atmosphere", "government", "remarks"}, fields)
+}
+
+func TestParseUWPExtensions(t *testing.T) {
+
+	def, errs := ParseUWP("Corgi 0101 CA6A643-9 N RI { -1 } (A46+2) [1716]", testTradeCodes)
+	assert.Empty(t, errs)
+
+	assert.Equal(t, -1, def.Extensions.Importance)
+	assert.Equal(t, &WorldEconomics{Resources: 10, Labor: 4, Infrastructure: 6, Efficiency: 2}, def.Extensions.Economic)
+	assert.Equal(t, &WorldCulturalExtension{Heterogeneity: 1, Acceptance: 7, Strangeness: 1, Symbols: 6}, def.Extensions.Cultural)
+
+	assert.Equal(t, "Corgi 0101 CA6A643-9 N RI", def.ToUWP(testTradeCodes, false))
+	assert.Equal(t, "Corgi 0101 CA6A643-9 N RI { -1 } (A46+2) [1716]", def.ToUWP(testTradeCodes, true))
+}
+
+func TestParseBaseCodes(t *testing.T) {
+
+	bases, err := ParseBaseCodes("NS")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"naval", "scout"}, bases)
+
+	//the older SEC format combines naval and scout bases as A
+	bases, err = ParseBaseCodes("A")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"naval", "scout"}, bases)
+
+	bases, err = ParseBaseCodes("-")
+	assert.NoError(t, err)
+	assert.Empty(t, bases)
+
+	_, err = ParseBaseCodes("K")
+	assert.Error(t, err)
+}
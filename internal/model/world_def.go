@@ -11,8 +11,34 @@ type WorldStarportInfo struct {
 	BerthingCost int  `json:"berthing-cost"`
 }
 
+// WorldEconomics is the T5 Economic extension (Ex) e.g. (A46+2)
+type WorldEconomics struct {
+	Resources      int `json:"resources"`
+	Labor          int `json:"labor"`
+	Infrastructure int `json:"infrastructure"`
+	Efficiency     int `json:"efficiency"`
+}
+
+// WorldCulturalExtension is the T5 Cultural extension (Cx) e.g. [1716]
+type WorldCulturalExtension struct {
+	Heterogeneity int `json:"heterogeneity"`
+	Acceptance    int `json:"acceptance"`
+	Strangeness   int `json:"strangeness"`
+	Symbols       int `json:"symbols"`
+}
+
+// WorldExtensions holds the T5 extensions to the UWP. These are optional as they are not part of
+// the UWP in the core rules
+type WorldExtensions struct {
+	Importance int                     `json:"importance"`
+	Economic   *WorldEconomics         `json:"economic"`
+	Cultural   *WorldCulturalExtension `json:"cultural"`
+}
+
 type WorldDefinition struct {
+	Name         string             `json:"name"`
 	SubsectorLoc string             `json:"subsector-loc"`
+	System       *StarSystem        `json:"system,omitempty"`
 	Starport     *WorldStarportInfo `json:"starport"`
 
 	Size          int `json:"size"`
@@ -32,4 +58,6 @@ type WorldDefinition struct {
 	HabitabilityZone string          `json:"habitability-zone"`
 	Factions         []*WorldFaction `json:"factions"`
 	Culture          int             `json:"culture"`
+
+	Extensions *WorldExtensions `json:"extensions,omitempty"`
 }
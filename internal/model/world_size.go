@@ -2,6 +2,9 @@ package model
 
 import (
 	"encoding/json"
+	"strconv"
+	"strings"
+	"unicode"
 )
 
 type WorldSizeMap map[int]*WorldSize
@@ -31,3 +34,15 @@ func WorldSizesFromFile(b []byte) (WorldSizeMap, error) {
 	}
 	return dataMap, nil
 }
+
+// DiameterKm is the diameter in kilometres. Size 0 is given as less than 1000km, which is taken as 1000km
+func (w *WorldSize) DiameterKm() int {
+	digits := strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, w.Diameter)
+	km, _ := strconv.Atoi(digits)
+	return km
+}
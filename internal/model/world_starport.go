@@ -2,6 +2,7 @@ package model
 
 import (
 	"encoding/json"
+	"strings"
 )
 
 type WorldStarportMap map[int]*WorldStarport
@@ -10,12 +11,14 @@ type WorldStarportJSON struct {
 	WorldStarportData []WorldStarport `json:"starports"`
 }
 
+// WorldStarport is a starport quality (pg 257). Berthing costs 1D x BerthingCost credits
 type WorldStarport struct {
-	Value      int    `json:"value"`
-	Code       string `json:"code"`
-	Quality    string `json:"quality"`
-	Fuel       string `json:"fuel"`
-	Facilities string `json:"facilities"`
+	Value        int    `json:"value"`
+	Code         string `json:"code"`
+	Quality      string `json:"quality"`
+	Fuel         string `json:"fuel"`
+	Facilities   string `json:"facilities"`
+	BerthingCost int    `json:"berthing-cost"`
 }
 
 func WorldStarportsFromFile(b []byte) (WorldStarportMap, error) {
@@ -28,7 +31,28 @@ func WorldStarportsFromFile(b []byte) (WorldStarportMap, error) {
 
 	dataMap := make(WorldStarportMap)
 	for _, d := range data.WorldStarportData {
-		dataMap[d.Value] = &WorldStarport{Value: d.Value, Code: d.Code, Quality: d.Quality, Fuel: d.Fuel, Facilities: d.Facilities}
+		dataMap[d.Value] = &WorldStarport{Value: d.Value, Code: d.Code, Quality: d.Quality, Fuel: d.Fuel, Facilities: d.Facilities, BerthingCost: d.BerthingCost}
 	}
 	return dataMap, nil
 }
+
+// ByCode finds the starport for a starport code (e.g. 'A'). Several rolls give the same code, and all of them
+// describe the same starport
+func (m WorldStarportMap) ByCode(code string) (*WorldStarport, bool) {
+	for _, sp := range m {
+		if strings.EqualFold(sp.Code, code) {
+			return sp, true
+		}
+	}
+	return nil, false
+}
+
+// HasRefinedFuel is true if the starport sells refined fuel
+func (s *WorldStarport) HasRefinedFuel() bool {
+	return strings.HasPrefix(strings.ToLower(s.Fuel), "refined")
+}
+
+// HasUnrefinedFuel is true if the starport sells unrefined fuel
+func (s *WorldStarport) HasUnrefinedFuel() bool {
+	return strings.Contains(strings.ToLower(s.Fuel), "unrefined")
+}
@@ -3,6 +3,9 @@ package model
 import (
 	"strings"
 
+	"tas/internal/hexgrid"
+	"tas/internal/util"
+
 	"github.com/rs/zerolog"
 )
 
@@ -26,6 +29,12 @@ import (
 	N					-> List of bases present: (N)aval, (M)ilitary, (S)cout and/or (C)orsair
 	RI WA			-> Any number of Trade Code abbreviations (e.g. rich and waterworld in this example)
 	A					-> A Travel Zone indicator (G)reen, (A)mber or (R)ed
+
+	Published sector data often adds the T5 extensions, for example { 2 } (A46+2) [1716] where:
+
+	{ 2 }			-> Importance (Ix) of the world
+	(A46+2)		-> Economic extension (Ex): resources, labor, infrastructure and efficiency
+	[1716]		-> Cultural extension (Cx): heterogeneity, acceptance, strangeness and symbols
 */
 
 const (
@@ -49,11 +58,20 @@ type WorldSummary struct {
 	Bases         []string `json:"bases"`
 	TradeCodes    []string `json:"trade-codes"`
 	TravelZone    string   `json:"travel-zone"`
+	Importance    string   `json:"importance,omitempty"`
+	Economic      string   `json:"economic,omitempty"`
+	Cultural      string   `json:"cultural,omitempty"`
+	PBG           string   `json:"pbg,omitempty"`
+	Stars         string   `json:"stars,omitempty"`
+	Allegiance    string   `json:"allegiance,omitempty"`
+	Seed          int64    `json:"seed"`
 
 	ExtendedData ExtendedWorldSummary `json:"extended-data"`
+	Audit        []*util.AuditEntry   `json:"audit,omitempty"`
 }
 
-func (w WorldSummary) ToUWP() string {
+// ToUWP writes the summary as a UWP. The T5 extensions are only included if asked for
+func (w WorldSummary) ToUWP(withExtensions bool) string {
 	var uwp strings.Builder
 
 	uwp.WriteString(w.Name)
@@ -82,9 +100,22 @@ func (w WorldSummary) ToUWP() string {
 		uwp.WriteString(sp + w.TravelZone)
 	}
 
+	if withExtensions {
+		for _, ext := range []string{w.Importance, w.Economic, w.Cultural} {
+			if ext != "" {
+				uwp.WriteString(sp + ext)
+			}
+		}
+	}
+
 	return uwp.String()
 }
 
+// Hex returns the world's hex location on the map, in the given sector (or sector 0,0)
+func (w WorldSummary) Hex(sector ...hexgrid.SectorOffset) (hexgrid.Hex, error) {
+	return hexgrid.Parse(w.HexLocation, sector...)
+}
+
 func (w WorldSummary) ToFileName() string {
 	var sb strings.Builder
 
@@ -120,7 +151,7 @@ func (w WorldSummary) ToLongFileName() string {
 }
 
 func (w WorldSummary) MarshalZerologObject(e *zerolog.Event) {
-	val := w.ToUWP()
+	val := w.ToUWP(false)
 	e.Str("UWP", val)
 }
 
@@ -189,7 +220,49 @@ type ExtendedBaseSummary struct {
 	Description string `json:"description"`
 }
 
+type ExtendedTradeCodeSummary struct {
+	Code        string `json:"code"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type ExtendedImportanceSummary struct {
+	Value       int    `json:"value"`
+	Description string `json:"description"`
+}
+
+type ExtendedEconomicSummary struct {
+	Resources      int `json:"resources"`
+	Labor          int `json:"labor"`
+	Infrastructure int `json:"infrastructure"`
+	Efficiency     int `json:"efficiency"`
+}
+
+type ExtendedCulturalSummary struct {
+	Heterogeneity int `json:"heterogeneity"`
+	Acceptance    int `json:"acceptance"`
+	Strangeness   int `json:"strangeness"`
+	Symbols       int `json:"symbols"`
+}
+
+type ExtendedOrbitSummary struct {
+	Orbit    int    `json:"orbit"`
+	Zone     string `json:"zone"`
+	Contents string `json:"contents"`
+}
+
+type ExtendedSystemSummary struct {
+	Primary            string                 `json:"primary"`
+	Companions         []string               `json:"companions"`
+	PlanetoidBelts     int                    `json:"planetoid-belts"`
+	GasGiants          int                    `json:"gas-giants"`
+	HabitableZoneOrbit int                    `json:"habitable-zone-orbit"`
+	MainworldOrbit     int                    `json:"mainworld-orbit"`
+	Orbits             []ExtendedOrbitSummary `json:"orbits"`
+}
+
 type ExtendedWorldSummary struct {
+	SystemDetails        *ExtendedSystemSummary       `json:"system,omitempty"`
 	StarportDetails      ExtendedStarportSummary      `json:"starport"`
 	SizeDetails          ExtendedSizeSummary          `json:"size"`
 	AtmosphereDetails    ExetendedAtmosphereSummary   `json:"atmosphere"`
@@ -201,5 +274,9 @@ type ExtendedWorldSummary struct {
 	LawDetails           ExtendedLawSummary           `json:"law-level"`
 	TechDetails          ExtendedTechLevelSummary     `json:"tech-level"`
 	BaseDetails          []ExtendedBaseSummary        `json:"bases"`
+	TradeCodeDetails     []ExtendedTradeCodeSummary   `json:"trade-codes"`
+	ImportanceDetails    *ExtendedImportanceSummary   `json:"importance,omitempty"`
+	EconomicDetails      *ExtendedEconomicSummary     `json:"economic,omitempty"`
+	CulturalDetails      *ExtendedCulturalSummary     `json:"cultural,omitempty"`
 	LongDescription      string                       `json:"long-description"`
 }
@@ -2,17 +2,34 @@ package model
 
 import (
 	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
 )
 
+// WorldTradeCodeMap is the trade code catalogue, keyed by code (e.g. 'Ag'). World generation, UWPs, trade data
+// and trade goods all use these codes, so a world's trade codes never need translating
 type WorldTradeCodeMap map[string]*WorldTradeCode
 
 type WorldTradeCodeJSON struct {
 	WorldTradeCodeData []WorldTradeCode `json:"codes"`
 }
 
+// TradeCodeCriterion limits one UWP field for a trade code to a range, to a list of values or both. A missing
+// min or max leaves that end of the range open
+type TradeCodeCriterion struct {
+	Min    *int  `json:"min,omitempty"`
+	Max    *int  `json:"max,omitempty"`
+	Values []int `json:"values,omitempty"`
+}
+
+// WorldTradeCode is a trade code (pg 260) and the UWP values a world must have to be given it. Criteria are keyed
+// by UWP field: size, atmosphere, hydrographics, population, government, law-level and tech-level
 type WorldTradeCode struct {
-	Name         string `json:"name"`
-	Abbreviation string `json:"abbreviation"`
+	Code        string                         `json:"code"`
+	Name        string                         `json:"name"`
+	Description string                         `json:"description"`
+	Criteria    map[string]*TradeCodeCriterion `json:"criteria"`
 }
 
 func WorldTradeCodesFromFile(b []byte) (WorldTradeCodeMap, error) {
@@ -25,7 +42,88 @@ func WorldTradeCodesFromFile(b []byte) (WorldTradeCodeMap, error) {
 
 	dataMap := make(WorldTradeCodeMap)
 	for _, d := range data.WorldTradeCodeData {
-		dataMap[d.Name] = &WorldTradeCode{Name: d.Name, Abbreviation: d.Abbreviation}
+		tc := d
+		if _, ok := dataMap.ByCode(tc.Code); ok {
+			return nil, fmt.Errorf("trade code %s is listed more than once", tc.Code)
+		}
+		for field := range tc.Criteria {
+			if _, ok := tradeCodeFieldsByKey()[field]; !ok {
+				return nil, fmt.Errorf("trade code %s has a criterion for unknown UWP field %s", tc.Code, field)
+			}
+		}
+		dataMap[tc.Code] = &tc
 	}
 	return dataMap, nil
 }
+
+// ByCode finds a trade code by its code (e.g. 'Ri'), ignoring case
+func (m WorldTradeCodeMap) ByCode(code string) (*WorldTradeCode, bool) {
+	if tc, ok := m[code]; ok {
+		return tc, true
+	}
+	for _, tc := range m {
+		if strings.EqualFold(tc.Code, code) {
+			return tc, true
+		}
+	}
+	return nil, false
+}
+
+// Codes lists every code in the catalogue in alphabetical order
+func (m WorldTradeCodeMap) Codes() []string {
+	codes := make([]string, 0, len(m))
+	for c := range m {
+		codes = append(codes, c)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// CodesFor lists, in alphabetical order, the codes of every trade code the world meets the criteria for
+func (m WorldTradeCodeMap) CodesFor(def *WorldDefinition) []string {
+	codes := make([]string, 0)
+	for _, c := range m.Codes() {
+		if m[c].Matches(def) {
+			codes = append(codes, c)
+		}
+	}
+	return codes
+}
+
+// Matches is true if the world meets every criterion of the trade code
+func (tc *WorldTradeCode) Matches(def *WorldDefinition) bool {
+	fields := tradeCodeFieldsByKey()
+	for key, c := range tc.Criteria {
+		if !c.allows(fields[key].get(def)) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *TradeCodeCriterion) allows(v int) bool {
+	if c.Min != nil && v < *c.Min {
+		return false
+	}
+	if c.Max != nil && v > *c.Max {
+		return false
+	}
+	if len(c.Values) == 0 {
+		return true
+	}
+	for _, allowed := range c.Values {
+		if v == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// criteria use the UWP field names, hyphenated e.g. law-level
+func tradeCodeFieldsByKey() map[string]uwpField {
+	fields := make(map[string]uwpField)
+	for _, f := range append(uwpFields, uwpTechLevelField) {
+		fields[strings.ReplaceAll(f.name, " ", "-")] = f
+	}
+	return fields
+}
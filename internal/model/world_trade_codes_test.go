@@ -0,0 +1,37 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testTradeCodeJSON = `{"codes": [
+	{"code": "Ri", "name": "rich", "criteria": {"atmosphere": {"values": [6, 8]}, "population": {"min": 6, "max": 8}, "government": {"min": 4, "max": 9}}},
+	{"code": "Hi", "name": "high population", "criteria": {"population": {"min": 9}}},
+	{"code": "Va", "name": "vacuum", "criteria": {"atmosphere": {"max": 0}}}
+]}`
+
+func TestTradeCodeCatalogue(t *testing.T) {
+
+	codes, err := WorldTradeCodesFromFile([]byte(testTradeCodeJSON))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Hi", "Ri", "Va"}, codes.Codes())
+
+	tc, ok := codes.ByCode("RI")
+	assert.True(t, ok)
+	assert.Equal(t, "rich", tc.Name)
+
+	assert.Equal(t, []string{"Ri"}, codes.CodesFor(&WorldDefinition{Atmosphere: 6, Population: 7, Government: 4}))
+	assert.Equal(t, []string{"Hi", "Va"}, codes.CodesFor(&WorldDefinition{Atmosphere: 0, Population: 10}))
+	assert.Empty(t, codes.CodesFor(&WorldDefinition{Atmosphere: 7, Population: 7, Government: 4}))
+}
+
+func TestTradeCodeCatalogueErrors(t *testing.T) {
+
+	_, err := WorldTradeCodesFromFile([]byte(`{"codes": [{"code": "Ri"}, {"code": "RI"}]}`))
+	assert.Error(t, err)
+
+	_, err = WorldTradeCodesFromFile([]byte(`{"codes": [{"code": "Ri", "criteria": {"gravity": {"min": 1}}}]}`))
+	assert.Error(t, err)
+}
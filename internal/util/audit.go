@@ -0,0 +1,184 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	unlabelledRoll = "unlabelled roll"
+)
+
+// AuditEntry records a single roll of the dice: what the roll was for, the dice that came up,
+// the modifiers applied (and why), the modified total and the final value after any bounding
+type AuditEntry struct {
+	Label     string   `json:"label"`
+	Roll      string   `json:"roll"`
+	Dice      []int    `json:"dice"`
+	Modifiers []int    `json:"modifiers,omitempty"`
+	Reasons   []string `json:"reasons,omitempty"`
+	Total     int      `json:"total"`
+	Final     int      `json:"final"`
+}
+
+func (a *AuditEntry) String() string {
+	var sb strings.Builder
+
+	//a value worked out without rolling has no dice to show
+	if a.Roll == "" {
+		return fmt.Sprintf("%s: %d", a.Label, a.Total)
+	}
+
+	sb.WriteString(fmt.Sprintf("%s: %s %v", a.Label, a.Roll, a.Dice))
+	for _, m := range a.Modifiers {
+		if m != 0 {
+			sb.WriteString(fmt.Sprintf(" %+d", m))
+		}
+	}
+	sb.WriteString(fmt.Sprintf(" = %d", a.Total))
+	if a.Final != a.Total {
+		sb.WriteString(fmt.Sprintf(" (bounded to %d)", a.Final))
+	}
+	return sb.String()
+}
+
+// AuditTrail collects AuditEntries. All methods are safe to call on a nil AuditTrail, which allows
+// callers to label rolls without first checking if an audit was requested
+type AuditTrail struct {
+	Entries []*AuditEntry
+
+	label   string
+	reasons []string
+}
+
+// Label sets the label to be used for the next roll of the dice
+func (a *AuditTrail) Label(label string) {
+	if a == nil {
+		return
+	}
+	a.label = label
+	a.reasons = nil
+}
+
+// Reason notes why a modifier was applied. Reasons are attached to the next roll of the dice
+func (a *AuditTrail) Reason(reason string) {
+	if a == nil {
+		return
+	}
+	a.reasons = append(a.reasons, reason)
+}
+
+// Value records a value that is worked out without rolling (e.g. a sum of DMs), with the label and reasons
+// given since the last roll
+func (a *AuditTrail) Value(value int) {
+	if a == nil {
+		return
+	}
+	a.record("", nil, nil, value)
+}
+
+// Final records the final value (e.g. after bounding to a table's min/max) of the most recent roll
+func (a *AuditTrail) Final(value int) {
+	if a == nil || len(a.Entries) == 0 {
+		return
+	}
+	a.Entries[len(a.Entries)-1].Final = value
+}
+
+func (a *AuditTrail) record(roll string, dice []int, mods []int, total int) {
+	label := a.label
+	if label == "" {
+		label = unlabelledRoll
+	}
+
+	entry := &AuditEntry{
+		Label:     label,
+		Roll:      roll,
+		Dice:      dice,
+		Modifiers: mods,
+		Reasons:   a.reasons,
+		Total:     total,
+		Final:     total,
+	}
+	a.Entries = append(a.Entries, entry)
+
+	a.label = ""
+	a.reasons = nil
+}
+
+// recordingDice decorates a Dice, recording every roll to an AuditTrail. It rolls the wrapped
+// dice in exactly the same way the wrapped dice would roll itself, so results for a given seed
+// are the same whether or not they are being recorded
+type recordingDice struct {
+	dice  Dice
+	trail *AuditTrail
+}
+
+func NewRecordingDice(d Dice, trail *AuditTrail) Dice {
+	return &recordingDice{
+		dice:  d,
+		trail: trail,
+	}
+}
+
+func (r *recordingDice) Roll(mods ...int) int {
+	face := r.dice.Roll()
+	total := face + sumOf(mods)
+	r.trail.record("1D", []int{face}, mods, total)
+	return total
+}
+
+func (r *recordingDice) Sum(n int, mods ...int) int {
+	faces := make([]int, 0, n)
+	total := 0
+	for i := 1; i <= n; i++ {
+		face := r.dice.Roll()
+		faces = append(faces, face)
+		total += face
+	}
+	total += sumOf(mods)
+	r.trail.record(fmt.Sprintf("%dD", n), faces, mods, total)
+	return total
+}
+
+func (r *recordingDice) D66() int {
+	tens := r.dice.Roll()
+	ones := r.dice.Roll()
+	total := (tens * 10) + ones
+	r.trail.record("D66", []int{tens, ones}, nil, total)
+	return total
+}
+
+func (r *recordingDice) D3(mods ...int) int {
+	face := r.dice.D3()
+	total := face + sumOf(mods)
+	r.trail.record("D3", []int{face}, mods, total)
+	return total
+}
+
+func (r *recordingDice) Dx(sides int) int {
+	face := r.dice.Dx(sides)
+	r.trail.record(fmt.Sprintf("D%d", sides), []int{face}, nil, face)
+	return face
+}
+
+// Flux records both dice in a single entry, so the difference used is shown under one label
+func (r *recordingDice) Flux() int {
+	first := r.dice.Roll()
+	second := r.dice.Roll()
+	total := first - second
+	r.trail.record("Flux", []int{first, second}, nil, total)
+	return total
+}
+
+func (r *recordingDice) Seed() int64 {
+	return r.dice.Seed()
+}
+
+func sumOf(mods []int) int {
+	sum := 0
+	for _, m := range mods {
+		sum += m
+	}
+	return sum
+}
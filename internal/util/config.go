@@ -27,3 +27,14 @@ func (t *TASConfig) WithCmd(cmd *cobra.Command) (*TASConfig, error) {
 	t.Flags = cmd.Flags()
 	return t, nil
 }
+
+// Seed returns the value of the --seed flag if the user set it, otherwise a new time-based seed
+func (t *TASConfig) Seed() int64 {
+	if t.Flags != nil && t.Flags.Changed(SeedFlagName) {
+		seed, err := t.Flags.GetInt64(SeedFlagName)
+		if err == nil {
+			return seed
+		}
+	}
+	return NewSeed()
+}
@@ -12,6 +12,7 @@ const (
 	keyLogger keyType = "logger"
 	keyDice   keyType = "dice"
 	keyConfig keyType = "config"
+	keyAudit  keyType = "audit"
 )
 
 type TASContext struct {
@@ -22,6 +23,11 @@ func NewContext() *TASContext {
 	return &TASContext{ctx: context.Background()}
 }
 
+// Fork returns a copy of this context. Values added to the copy (e.g. a new Dice) do not affect the original
+func (t *TASContext) Fork() *TASContext {
+	return &TASContext{ctx: t.ctx}
+}
+
 func (t *TASContext) WithLogger(l *zerolog.Logger) *TASContext {
 	t.ctx = context.WithValue(t.ctx, keyLogger, l)
 	return t
@@ -31,8 +37,9 @@ func (t *TASContext) Logger() *zerolog.Logger {
 	return t.ctx.Value(keyLogger).(*zerolog.Logger)
 }
 
-func (t *TASContext) WithDice() *TASContext {
-	t.ctx = context.WithValue(t.ctx, keyDice, NewDice())
+// WithDice adds a Dice to the context. If no seed is given, a time-based seed is used
+func (t *TASContext) WithDice(seed ...int64) *TASContext {
+	t.ctx = context.WithValue(t.ctx, keyDice, NewDice(seed...))
 	return t
 }
 
@@ -48,3 +55,17 @@ func (t *TASContext) WithConfig(cfg *TASConfig) *TASContext {
 func (t *TASContext) Config() *TASConfig {
 	return t.ctx.Value(keyConfig).(*TASConfig)
 }
+
+// WithAudit wraps the context's Dice so that every roll is recorded in an AuditTrail. WithDice must be called first
+func (t *TASContext) WithAudit() *TASContext {
+	trail := &AuditTrail{}
+	t.ctx = context.WithValue(t.ctx, keyDice, NewRecordingDice(t.Dice(), trail))
+	t.ctx = context.WithValue(t.ctx, keyAudit, trail)
+	return t
+}
+
+// Audit returns the context's AuditTrail, or nil if rolls are not being recorded
+func (t *TASContext) Audit() *AuditTrail {
+	trail, _ := t.ctx.Value(keyAudit).(*AuditTrail)
+	return trail
+}
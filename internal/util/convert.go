@@ -3,6 +3,7 @@ package util
 import (
 	"fmt"
 	"strconv"
+	"strings"
 )
 
 const (
@@ -39,3 +40,24 @@ func BoundTo(i int, min int, max int) int {
 	}
 	return i
 }
+
+// extended hex (eHex) as used by UWPs: 0-9 then A-Z, skipping I and O to avoid confusion with 1 and 0
+const eHexDigits = "0123456789ABCDEFGHJKLMNPQRSTUVWXYZ"
+
+func IntAsEHexString(i int) (string, error) {
+	if i < 0 || i >= len(eHexDigits) {
+		return NaN, fmt.Errorf("unable to convert to eHex string. Value: %d exceeds range 0-%d", i, len(eHexDigits)-1)
+	}
+	return string(eHexDigits[i]), nil
+}
+
+func EHexAsInt(s string) (int, error) {
+	if len(s) != 1 {
+		return INVALID_int, fmt.Errorf("unable to parse: %s as eHex. Exactly one character is required", s)
+	}
+	n := strings.Index(eHexDigits, strings.ToUpper(s))
+	if n < 0 {
+		return INVALID_int, fmt.Errorf("unable to parse: %s as eHex", s)
+	}
+	return n, nil
+}
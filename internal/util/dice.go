@@ -1,13 +1,17 @@
 package util
 
 import (
+	"hash/fnv"
 	"math/rand"
+	"strconv"
 	"time"
 )
 
 const (
 	d6 = 6
 	d3 = 3
+
+	SeedFlagName = "seed"
 )
 
 type Dice interface {
@@ -16,18 +20,43 @@ type Dice interface {
 	D66() int
 	D3(mods ...int) int
 	Dx(sides int) int
+	Flux() int
+	Seed() int64
 }
 
 type dice struct {
+	seed    int64
 	randgen *rand.Rand
 }
 
-func NewDice() Dice {
+// NewDice creates a Dice seeded with the given seed. If no seed is given, a time-based seed is used
+func NewDice(seed ...int64) Dice {
+	s := NewSeed()
+	if len(seed) > 0 {
+		s = seed[0]
+	}
 	return &dice{
-		randgen: rand.New(rand.NewSource(time.Now().UnixNano())),
+		seed:    s,
+		randgen: rand.New(rand.NewSource(s)),
 	}
 }
 
+// NewSeed returns a seed suitable for a Dice when the user has not requested a specific seed
+func NewSeed() int64 {
+	return time.Now().UnixNano()
+}
+
+// DeriveSeed builds a new seed from a parent seed and any number of identifying parts (e.g. a hex location)
+// so that independent dice streams can be created that are still fully determined by the parent seed
+func DeriveSeed(seed int64, parts ...string) int64 {
+	hash := fnv.New64a()
+	hash.Write([]byte(strconv.FormatInt(seed, 10)))
+	for _, p := range parts {
+		hash.Write([]byte("/" + p))
+	}
+	return int64(hash.Sum64())
+}
+
 func (d *dice) Roll(mods ...int) int {
 	r := d.randgen.Intn(d6) + 1
 	for _, m := range mods {
@@ -67,3 +96,12 @@ func (d *dice) Dx(sides int) int {
 	r := d.randgen.Intn(sides) + 1
 	return r
 }
+
+// Flux is 1D-1D, giving -5 to +5
+func (d *dice) Flux() int {
+	return d.Roll() - d.Roll()
+}
+
+func (d *dice) Seed() int64 {
+	return d.seed
+}
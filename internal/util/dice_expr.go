@@ -0,0 +1,281 @@
+package util
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+/*
+	Dice expressions are the way dice are described in the rules, for example:
+
+	2D				-> roll two six-sided dice and sum them
+	2D-7+DM		-> as above, subtract 7 and add a DM supplied when rolling
+	D66				-> roll two dice, one for the tens and one for the ones
+	D3				-> a three-sided die
+	3D6, D20	-> any number of dice with any number of sides
+	2D+1 vs 8	-> roll against a target number. The Effect is the total less the target
+
+	A Boon rolls one extra die on the first six-sided dice term and keeps the highest, a Bane keeps the lowest
+*/
+
+const (
+	BoonBaneNone = 0
+	Boon         = 1
+	Bane         = -1
+
+	dmToken = "DM"
+
+	//no rule rolls anywhere near this many dice; the cap stops a typo rolling (and listing) millions of them
+	maxDiceCount = 100
+)
+
+var operatorSpacingRegEx = regexp.MustCompile(`\s*([+-])\s*`)
+var diceTermRegEx = regexp.MustCompile(`^([+-])?(DM|\d*D\d*|\d+)`)
+
+type diceTerm struct {
+	negative bool
+	count    int
+	sides    int
+	isD66    bool
+	isDM     bool
+	constant int
+}
+
+func (t *diceTerm) isDice() bool {
+	return t.count > 0
+}
+
+type DiceExpression struct {
+	Expression string
+	HasTarget  bool
+	Target     int
+	terms      []*diceTerm
+}
+
+// DiceCheckResult is the outcome of a roll against a target number
+type DiceCheckResult struct {
+	Target  int  `json:"target"`
+	Effect  int  `json:"effect"`
+	Success bool `json:"success"`
+}
+
+// DiceExpressionResult is the outcome of rolling a DiceExpression once
+type DiceExpressionResult struct {
+	Dice  []int            `json:"dice"`
+	Kept  []int            `json:"kept,omitempty"`
+	DM    int              `json:"dm"`
+	Total int              `json:"total"`
+	Check *DiceCheckResult `json:"check,omitempty"`
+}
+
+func (r *DiceExpressionResult) String() string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("%v", r.Dice))
+	if len(r.Kept) > 0 {
+		sb.WriteString(fmt.Sprintf(" keeping %v", r.Kept))
+	}
+	if r.DM != 0 {
+		sb.WriteString(fmt.Sprintf(" %+d", r.DM))
+	}
+	sb.WriteString(fmt.Sprintf(" = %d", r.Total))
+	if r.Check != nil {
+		outcome := "failure"
+		if r.Check.Success {
+			outcome = "success"
+		}
+		sb.WriteString(fmt.Sprintf(" vs %d, Effect %+d (%s)", r.Check.Target, r.Check.Effect, outcome))
+	}
+	return sb.String()
+}
+
+// ParseDiceExpression parses expressions such as "2D-7+DM", "D66", "3D" or "2D+1 vs 8"
+func ParseDiceExpression(expression string) (*DiceExpression, error) {
+
+	expr := &DiceExpression{
+		Expression: strings.TrimSpace(expression),
+	}
+
+	//normalise the expression so that the parser only deals with one form
+	raw := strings.ToUpper(strings.TrimSpace(expression))
+	if len(raw) == 0 {
+		return nil, fmt.Errorf("dice expression is empty")
+	}
+
+	//split off a target number, if any (e.g. "vs 8" or "vs 8+")
+	if parts := strings.Split(raw, "VS"); len(parts) > 1 {
+		if len(parts) > 2 {
+			return nil, fmt.Errorf("dice expression: %s has more than one target number", expression)
+		}
+		targetString := strings.TrimSuffix(strings.TrimSpace(parts[1]), "+")
+		target, err := strconv.Atoi(targetString)
+		if err != nil {
+			return nil, fmt.Errorf("dice expression: %s has invalid target number: %s", expression, parts[1])
+		}
+		expr.HasTarget = true
+		expr.Target = target
+		raw = strings.TrimSpace(parts[0])
+	}
+
+	//spaces are allowed around + and - only
+	raw = operatorSpacingRegEx.ReplaceAllString(raw, "$1")
+	if strings.ContainsAny(raw, " \t") {
+		return nil, fmt.Errorf("dice expression: %s is missing a + or - between terms", expression)
+	}
+
+	//parse each term in turn
+	for len(raw) > 0 {
+		match := diceTermRegEx.FindStringSubmatch(raw)
+		if match == nil {
+			return nil, fmt.Errorf("dice expression: %s cannot be understood starting at: %s", expression, raw)
+		}
+		if match[1] == "" && len(expr.terms) > 0 {
+			return nil, fmt.Errorf("dice expression: %s is missing a + or - before: %s", expression, match[2])
+		}
+
+		term, err := parseDiceTerm(match[2])
+		if err != nil {
+			return nil, fmt.Errorf("dice expression: %s has an invalid term. %w", expression, err)
+		}
+		term.negative = match[1] == "-"
+		expr.terms = append(expr.terms, term)
+
+		raw = raw[len(match[0]):]
+	}
+
+	return expr, nil
+}
+
+func parseDiceTerm(s string) (*diceTerm, error) {
+
+	term := &diceTerm{}
+
+	if s == dmToken {
+		term.isDM = true
+		return term, nil
+	}
+
+	idx := strings.Index(s, "D")
+	if idx < 0 {
+		constant, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, err
+		}
+		term.constant = constant
+		return term, nil
+	}
+
+	//dice - count defaults to 1 and sides default to 6
+	term.count = 1
+	term.sides = d6
+	if idx > 0 {
+		count, err := strconv.Atoi(s[:idx])
+		if err != nil || count < 1 {
+			return nil, fmt.Errorf("%s does not have a valid number of dice", s)
+		}
+		if count > maxDiceCount {
+			return nil, fmt.Errorf("%s rolls more than %d dice", s, maxDiceCount)
+		}
+		term.count = count
+	}
+	if idx < len(s)-1 {
+		sides, err := strconv.Atoi(s[idx+1:])
+		if err != nil || sides < 2 {
+			return nil, fmt.Errorf("%s does not have a valid number of sides", s)
+		}
+		term.sides = sides
+	}
+
+	if term.sides == 66 {
+		if term.count != 1 {
+			return nil, fmt.Errorf("%s is not valid, only a single D66 can be rolled", s)
+		}
+		term.isD66 = true
+	}
+
+	return term, nil
+}
+
+// Roll rolls the expression using the given dice. dm replaces the DM term (if any) and boonBane is one of Boon, Bane or BoonBaneNone
+func (e *DiceExpression) Roll(d Dice, dm int, boonBane int) *DiceExpressionResult {
+
+	result := &DiceExpressionResult{}
+	boonBaneApplied := false
+
+	for _, t := range e.terms {
+		value := 0
+
+		switch {
+		case t.isDM:
+			value = dm
+		case !t.isDice():
+			value = t.constant
+		case t.isD66:
+			tens := d.Roll()
+			ones := d.Roll()
+			result.Dice = append(result.Dice, tens, ones)
+			value = (tens * 10) + ones
+		default:
+			faces := make([]int, 0, t.count+1)
+			n := t.count
+			applyBoonBane := boonBane != BoonBaneNone && t.sides == d6 && !boonBaneApplied
+			if applyBoonBane {
+				n++
+			}
+			for i := 0; i < n; i++ {
+				faces = append(faces, rollSides(d, t.sides))
+			}
+			result.Dice = append(result.Dice, faces...)
+
+			kept := faces
+			if applyBoonBane {
+				kept = keep(faces, t.count, boonBane == Boon)
+				result.Kept = append(result.Kept, kept...)
+				boonBaneApplied = true
+			}
+			value = sumOf(kept)
+		}
+
+		if t.negative {
+			value = -value
+		}
+		if !t.isDice() || t.isDM {
+			result.DM += value
+		}
+		result.Total += value
+	}
+
+	if e.HasTarget {
+		result.Check = &DiceCheckResult{
+			Target:  e.Target,
+			Effect:  result.Total - e.Target,
+			Success: result.Total >= e.Target,
+		}
+	}
+
+	return result
+}
+
+func rollSides(d Dice, sides int) int {
+	switch sides {
+	case d6:
+		return d.Roll()
+	case d3:
+		return d.D3()
+	}
+	return d.Dx(sides)
+}
+
+// keep returns n of the given faces, either the highest or lowest
+func keep(faces []int, n int, highest bool) []int {
+	sorted := make([]int, len(faces))
+	copy(sorted, faces)
+	sort.Ints(sorted)
+	if highest {
+		return sorted[len(sorted)-n:]
+	}
+	return sorted[:n]
+}
@@ -0,0 +1,62 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDiceExpression(t *testing.T) {
+
+	valid := []string{"2D", "2D-7+DM", "D66", "D3", "3D", "2d6+1", "2D+1 vs 8", "2D vs 8+", "D20-2", "4", "100D"}
+	for _, v := range valid {
+		_, err := ParseDiceExpression(v)
+		assert.NoError(t, err, "expression %s should parse", v)
+	}
+
+	invalid := []string{"", "2X", "2D+", "2D 3", "2D vs", "2D vs 8 vs 9", "2D66", "0D", "D1", "101D", "1000000D6"}
+	for _, v := range invalid {
+		_, err := ParseDiceExpression(v)
+		assert.Error(t, err, "expression %s should not parse", v)
+	}
+}
+
+func TestDiceExpressionRollBounds(t *testing.T) {
+
+	d := NewDice(1)
+	expr, _ := ParseDiceExpression("2D-7+DM vs 0")
+	for i := 0; i < 1000; i++ {
+		r := expr.Roll(d, 3, BoonBaneNone)
+		assert.GreaterOrEqual(t, r.Total, -2, "2D-7+3 cannot be lower than -2")
+		assert.LessOrEqual(t, r.Total, 8, "2D-7+3 cannot be higher than 8")
+		assert.Equal(t, r.Total, r.Check.Effect, "effect against a target of 0 must equal the total")
+		assert.Equal(t, -4, r.DM, "DM must include constants and the supplied DM")
+	}
+
+	expr, _ = ParseDiceExpression("D66")
+	for i := 0; i < 1000; i++ {
+		r := expr.Roll(d, 0, BoonBaneNone)
+		assert.Contains(t, []int{1, 2, 3, 4, 5, 6}, r.Total/10, "D66 tens must be a D6")
+		assert.Contains(t, []int{1, 2, 3, 4, 5, 6}, r.Total%10, "D66 ones must be a D6")
+	}
+}
+
+func TestDiceExpressionBoonAndBane(t *testing.T) {
+
+	iterations := 100000
+	expr, _ := ParseDiceExpression("2D")
+	d := NewDice(1)
+
+	boonSum, baneSum := 0, 0
+	for i := 0; i < iterations; i++ {
+		r := expr.Roll(d, 0, Boon)
+		assert.Len(t, r.Dice, 3, "a boon rolls an extra die")
+		assert.Len(t, r.Kept, 2, "a boon keeps two dice")
+		boonSum += r.Total
+		baneSum += expr.Roll(d, 0, Bane).Total
+	}
+
+	//3D keep highest two averages ~8.46, keep lowest two ~5.54
+	assert.InDelta(t, 8.46, float32(boonSum)/float32(iterations), .05, "boon is not keeping the highest dice")
+	assert.InDelta(t, 5.54, float32(baneSum)/float32(iterations), .05, "bane is not keeping the lowest dice")
+}
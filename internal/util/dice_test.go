@@ -61,3 +61,43 @@ func TestDiceSumWithMods(t *testing.T) {
 
 	assert.InDelta(t, avg, 21, .01, "base 6D roll (with modifiers) is not generating the expected average")
 }
+
+func TestDiceSeedIsRepeatable(t *testing.T) {
+
+	d1 := NewDice(12345)
+	d2 := NewDice(12345)
+	for i := 0; i < 1000; i++ {
+		assert.Equal(t, d1.Roll(), d2.Roll(), "dice with the same seed must roll the same values")
+	}
+	assert.Equal(t, int64(12345), d1.Seed(), "dice must report the seed they were created with")
+}
+
+func TestDeriveSeed(t *testing.T) {
+
+	assert.Equal(t, DeriveSeed(12345, "0304"), DeriveSeed(12345, "0304"), "derived seeds must be repeatable")
+	assert.NotEqual(t, DeriveSeed(12345, "0304"), DeriveSeed(12345, "0305"), "different hexes must derive different seeds")
+	assert.NotEqual(t, DeriveSeed(12345, "0304"), DeriveSeed(54321, "0304"), "different parent seeds must derive different seeds")
+}
+
+func TestDiceFlux(t *testing.T) {
+
+	d := NewDice(7)
+	for i := 0; i < 1000; i++ {
+		f := d.Flux()
+		assert.GreaterOrEqual(t, f, -5)
+		assert.LessOrEqual(t, f, 5)
+	}
+
+	//a recorded flux gives the same result as an unrecorded one, with both dice in a single labelled entry
+	trail := &AuditTrail{}
+	recorded := NewRecordingDice(NewDice(3), trail)
+	trail.Label("strangeness flux")
+	f := recorded.Flux()
+	assert.Equal(t, NewDice(3).Flux(), f)
+	assert.Len(t, trail.Entries, 1)
+	entry := trail.Entries[0]
+	assert.Equal(t, "strangeness flux", entry.Label)
+	assert.Len(t, entry.Dice, 2)
+	assert.Equal(t, entry.Dice[0]-entry.Dice[1], entry.Final)
+	assert.Equal(t, f, entry.Final)
+}
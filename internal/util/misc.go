@@ -3,6 +3,3 @@ package util
 const (
 	ToFileFlagName = "tofile"
 )
-
-var ValidTradeCodeSet = map[string]struct{}{"AG": {}, "AS": {}, "BA": {}, "DE": {}, "FL": {}, "GA": {}, "HI": {},
-	"HT": {}, "IC": {}, "IN": {}, "LO": {}, "NT": {}, "NA": {}, "NI": {}, "PO": {}, "RI": {}, "VA": {}, "WA": {}}
@@ -0,0 +1,35 @@
+package util
+
+import (
+	"strings"
+)
+
+/*
+	Names are built from two or three syllables, each an optional onset, a vowel and an optional coda. This keeps
+	names pronounceable while giving tens of thousands of them, for when a list of names runs out
+*/
+
+var (
+	nameOnsets = []string{"", "b", "br", "c", "ch", "d", "dr", "f", "g", "gr", "h", "j", "k", "kh", "l", "m", "n", "p",
+		"pr", "qu", "r", "s", "sh", "st", "t", "th", "tr", "v", "z"}
+	nameVowels = []string{"a", "e", "i", "o", "u", "a", "e", "o", "ae", "ai", "ei", "ia", "io", "ou"}
+	nameCodas  = []string{"", "", "", "", "n", "r", "s", "l", "m", "th", "x", "nd", "rk"}
+)
+
+// GenerateName builds a pronounceable name from random syllables using the given dice
+func GenerateName(d Dice) string {
+	var sb strings.Builder
+
+	syllables := 2
+	if d.Roll() >= 5 {
+		syllables = 3
+	}
+	for i := 0; i < syllables; i++ {
+		sb.WriteString(nameOnsets[d.Dx(len(nameOnsets))-1])
+		sb.WriteString(nameVowels[d.Dx(len(nameVowels))-1])
+		sb.WriteString(nameCodas[d.Dx(len(nameCodas))-1])
+	}
+
+	name := sb.String()
+	return strings.ToUpper(name[:1]) + name[1:]
+}
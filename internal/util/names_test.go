@@ -0,0 +1,23 @@
+package util
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateName(t *testing.T) {
+
+	pattern := regexp.MustCompile(`^[A-Z][a-z]{1,17}$`)
+	names := make(map[string]struct{})
+	d := NewDice(42)
+	for i := 0; i < 1000; i++ {
+		name := GenerateName(d)
+		assert.Regexp(t, pattern, name)
+		names[name] = struct{}{}
+	}
+	assert.Greater(t, len(names), 950, "generated names should rarely repeat")
+
+	assert.Equal(t, GenerateName(NewDice(7)), GenerateName(NewDice(7)), "the same seed should give the same name")
+}
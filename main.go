@@ -1,7 +1,11 @@
 package main
 
 import (
+	h "tas/internal/cmd/helpers"
+	"tas/internal/cmd/ledger"
 	"tas/internal/cmd/polish"
+	"tas/internal/cmd/roll"
+	"tas/internal/cmd/route"
 	"tas/internal/cmd/sector"
 	"tas/internal/cmd/trade"
 	"tas/internal/cmd/world"
@@ -16,14 +20,20 @@ func main() {
 	var rootCmd = &cobra.Command{}
 	var LogLevel string
 	var ToFile bool
+	var Seed int64
 	rootCmd.PersistentFlags().StringVar(&LogLevel, util.LogLevelFlagName, util.LogLevelWarn, "logging level (debug, info, warn, error or off")
 	rootCmd.PersistentFlags().BoolVar(&ToFile, util.ToFileFlagName, false, "set to also write output to an output file")
+	rootCmd.PersistentFlags().Int64Var(&Seed, util.SeedFlagName, 0, "seed for the dice so that output can be regenerated. A random seed is used if not set")
 
 	//world command
 	var GenScheme string
 	var Longform bool
+	var Explain bool
+	var Extensions bool
 	world.WorldCmdConfig.PersistentFlags().StringVar(&GenScheme, world.WorldGenSchemeFlagName, "standard", "name of world generator scheme (standard, custom)")
 	world.WorldCmdConfig.PersistentFlags().BoolVar(&Longform, world.LongformOutputFlagName, false, "set to display detailed world information rather than UWP)")
+	world.WorldCmdConfig.PersistentFlags().BoolVar(&Explain, world.ExplainFlagName, false, "set to record every dice roll used to generate the world (shown in longform and JSON output)")
+	world.WorldCmdConfig.PersistentFlags().BoolVar(&Extensions, world.ExtensionsFlagName, false, "set to add the T5 importance, economic and cultural extensions to the UWP")
 	rootCmd.AddCommand(world.WorldCmdConfig)
 
 	//world debug command (world sub command)
@@ -32,21 +42,110 @@ func main() {
 	world.WorldDebugCmdConfig.PersistentFlags().BoolVar(&MaxIterations, world.MaxLoopSizeFlagName, false, "set to generate max number of worlds rather than just a rough subsector count)")
 	world.WorldCmdConfig.AddCommand(world.WorldDebugCmdConfig)
 
+	//world explain
+	world.WorldCmdConfig.AddCommand(world.WorldExplainCmdConfig)
+
 	//trade command
 	var TradeFileName string
 	trade.TradeCmdConfig.PersistentFlags().StringVar(&TradeFileName, trade.TradeFileFlagName, "trade-data.json", "name of file in data-local that holds character and world trade facts")
+	var LedgerFileName string
+	trade.TradeCmdConfig.PersistentFlags().StringVar(&LedgerFileName, h.LedgerFileFlagName, h.DefaultLedgerFilename, "name of the campaign ledger file in data-local, whose current world is used when the current world is not given")
+	var ShipFileName string
+	trade.TradeCmdConfig.PersistentFlags().StringVar(&ShipFileName, trade.ShipFileFlagName, trade.DefaultShipFilename, "name of file in data-local that holds the ship profile and crew (the character data in the trade data file is used if there is no ship.json)")
+	var Effect, Skill int
+	trade.TradeCmdConfig.PersistentFlags().IntVar(&Effect, trade.EffectFlagName, 0, "set to the Effect of the Broker, Carouse or Streetwise check to resolve passenger traffic into passengers and fares")
+	trade.TradeCmdConfig.PersistentFlags().IntVar(&Skill, trade.SkillFlagName, 0, "set to the Broker, Carouse or Streetwise skill level to roll the check and resolve passenger traffic (instead of --effect)")
+	var FreightEffect, FreightSkill, Cargo int
+	trade.TradeCmdConfig.PersistentFlags().IntVar(&FreightEffect, trade.FreightEffectFlagName, 0, "set to the Effect of the Broker or Streetwise check to resolve freight traffic into lots and a manifest")
+	trade.TradeCmdConfig.PersistentFlags().IntVar(&FreightSkill, trade.FreightSkillFlagName, 0, "set to the Broker or Streetwise skill level to roll the check and resolve freight traffic (instead of --freight-effect)")
+	trade.TradeCmdConfig.PersistentFlags().IntVar(&Cargo, trade.CargoFlagName, 0, "tons of cargo space free for freight on this trip (the ship's cargo tons if not set)")
 	rootCmd.AddCommand(trade.TradeCmdConfig)
 
 	//speculative trade command (trade sub command)
+	var Broker, HiredBroker, BrokerFee int
+	trade.SpecTradeCmdConfig.PersistentFlags().IntVar(&Broker, trade.BrokerFlagName, 0, "set to the party's Broker skill to roll the price of every lot on the Modified Price table")
+	trade.SpecTradeCmdConfig.PersistentFlags().IntVar(&HiredBroker, trade.HiredBrokerFlagName, 0, "set to a hired broker's Broker skill to roll prices with their skill and a flat DM+2 (instead of --broker)")
+	trade.SpecTradeCmdConfig.PersistentFlags().IntVar(&BrokerFee, trade.BrokerFeeFlagName, trade.DefaultBrokerFeePercent, "percentage of the price a hired broker takes as their fee")
 	trade.TradeCmdConfig.AddCommand(trade.SpecTradeCmdConfig)
 
+	//trip economics command (trade sub command)
+	var Fuel string
+	trade.EconomicsCmdConfig.PersistentFlags().StringVar(&Fuel, trade.FuelFlagName, trade.FuelRefined, "type of fuel bought for the trip (refined or unrefined)")
+	trade.TradeCmdConfig.AddCommand(trade.EconomicsCmdConfig)
+
+	//trade plan command (trade sub command)
+	var Trials, Budget, PlanBroker, PlanHiredBroker, PlanBrokerFee int
+	trade.PlanCmdConfig.PersistentFlags().IntVar(&PlanBroker, trade.BrokerFlagName, 0, "set to the party's Broker skill (the crew's best in the ship profile if not set)")
+	trade.PlanCmdConfig.PersistentFlags().IntVar(&PlanHiredBroker, trade.HiredBrokerFlagName, 0, "set to a hired broker's Broker skill to plan with their skill, a flat DM+2 and their fee (instead of --broker)")
+	trade.PlanCmdConfig.PersistentFlags().IntVar(&PlanBrokerFee, trade.BrokerFeeFlagName, trade.DefaultBrokerFeePercent, "percentage of the price a hired broker takes as their fee")
+	trade.PlanCmdConfig.PersistentFlags().IntVar(&Trials, trade.TrialsFlagName, trade.DefaultTrials, "number of times the purchase and sale prices are simulated for each good and destination")
+	trade.PlanCmdConfig.PersistentFlags().IntVar(&Budget, trade.BudgetFlagName, 0, "credits to spend on cargo (the ledger's credits if the current world is the ledger's, otherwise not limited)")
+	trade.TradeCmdConfig.AddCommand(trade.PlanCmdConfig)
+
 	//sector command
 	var WorldGenScheme string
 	sector.SectorCmdConfig.PersistentFlags().StringVar(&WorldGenScheme, world.WorldGenSchemeFlagName, "standard", "name of world generator scheme (standard, custom)")
+	var Hex string
+	var Subsector string
+	var Map bool
+	var Format string
+	var EmitTradeData string
+	var Reroll int
+	sector.SectorCmdConfig.PersistentFlags().BoolVar(&Extensions, world.ExtensionsFlagName, false, "set to add the T5 importance, economic and cultural extensions to each UWP")
+	sector.SectorCmdConfig.PersistentFlags().BoolVar(&Explain, world.ExplainFlagName, false, "set to record and display every dice roll used to generate each world")
+	sector.SectorCmdConfig.PersistentFlags().BoolVar(&Map, sector.MapFlagName, false, "set to also draw the sector (or subsector) as an SVG hex map in the output directory")
+	sector.SectorCmdConfig.PersistentFlags().StringVar(&Format, sector.FormatFlagName, "", "set to also export the sector for travellermap and other tools (t5tab, sec) in the output directory")
+	sector.SectorCmdConfig.PersistentFlags().StringVar(&Subsector, sector.SubsectorFlagName, "", "set to generate only the given subsector (A-P) rather than the full sector")
+	sector.SectorCmdConfig.PersistentFlags().StringVar(&Hex, sector.HexFlagName, "", "set to generate only the world in the given hex (e.g. 0304)")
+	sector.SectorCmdConfig.PersistentFlags().IntVar(&Reroll, sector.RerollFlagName, 0, "set (with --hex) to reroll the world in that hex without changing any other hex. Each number gives a different world")
+	sector.SectorCmdConfig.PersistentFlags().StringVar(&EmitTradeData, sector.EmitTradeDataFlagName, "", "set to write the sector's worlds into a trade data file in data-local (trade-data.json unless a name is given with --emit-trade-data=<file>), keeping its character data")
+	sector.SectorCmdConfig.PersistentFlags().Lookup(sector.EmitTradeDataFlagName).NoOptDefVal = sector.DefaultTradeDataFilename
 	rootCmd.AddCommand(sector.SectorCmdConfig)
 
+	//import a published sector (sector sub command)
+	sector.SectorCmdConfig.AddCommand(sector.SectorImportCmdConfig)
+
+	//roll command
+	var Boon, Bane bool
+	var Times, DM int
+	roll.RollCmdConfig.PersistentFlags().BoolVar(&Boon, roll.BoonFlagName, false, "set to roll with a boon (roll an extra die and keep the highest)")
+	roll.RollCmdConfig.PersistentFlags().BoolVar(&Bane, roll.BaneFlagName, false, "set to roll with a bane (roll an extra die and keep the lowest)")
+	roll.RollCmdConfig.PersistentFlags().IntVar(&Times, roll.TimesFlagName, 1, "number of times to roll the expression")
+	roll.RollCmdConfig.PersistentFlags().IntVar(&DM, roll.DMFlagName, 0, "value used for DM in the expression (e.g. 2D-7+DM)")
+	rootCmd.AddCommand(roll.RollCmdConfig)
+
+	//route command
+	var Jump, FuelParsecs int
+	var Refined, AvoidRed bool
+	var RouteShipFileName string
+	route.RouteCmdConfig.PersistentFlags().StringVar(&RouteShipFileName, trade.ShipFileFlagName, trade.DefaultShipFilename, "name of file in data-local that holds the ship profile, whose jump rating, fuel and m-drive are used")
+	route.RouteCmdConfig.PersistentFlags().IntVar(&Jump, route.JumpFlagName, 1, "the ship's jump rating (the ship profile's if not set)")
+	route.RouteCmdConfig.PersistentFlags().IntVar(&FuelParsecs, route.FuelParsecsFlagName, 1, "parsecs the ship can jump on full tanks (the ship profile's fuel if not set, or one jump at the jump rating)")
+	route.RouteCmdConfig.PersistentFlags().BoolVar(&Refined, route.RefinedFlagName, false, "set to only take on refined fuel, unless there is no route that way")
+	route.RouteCmdConfig.PersistentFlags().BoolVar(&AvoidRed, route.AvoidRedFlagName, false, "set to keep out of Red Zones along the way")
+	rootCmd.AddCommand(route.RouteCmdConfig)
+
 	//polish command
 	rootCmd.AddCommand(polish.PolishCmdConfig)
 
+	//ledger command and its sub commands
+	var Credits int
+	var Date string
+	var LedgerLedgerFileName, LedgerTradeFileName, LedgerShipFileName string
+	ledger.LedgerCmdConfig.PersistentFlags().StringVar(&LedgerLedgerFileName, h.LedgerFileFlagName, h.DefaultLedgerFilename, "name of the campaign ledger file in data-local")
+	ledger.LedgerStartCmdConfig.PersistentFlags().IntVar(&Credits, ledger.CreditsFlagName, 0, "credits the campaign starts with")
+	ledger.LedgerStartCmdConfig.PersistentFlags().StringVar(&Date, ledger.DateFlagName, ledger.DefaultDate, "Imperial date the campaign starts on (day-year, e.g. 001-1105)")
+	ledger.LedgerCmdConfig.AddCommand(ledger.LedgerStartCmdConfig)
+	ledger.LedgerCmdConfig.AddCommand(ledger.LedgerBuyCmdConfig)
+	ledger.LedgerCmdConfig.AddCommand(ledger.LedgerSellCmdConfig)
+	ledger.LedgerCmdConfig.AddCommand(ledger.LedgerLoadCmdConfig)
+	ledger.LedgerCmdConfig.AddCommand(ledger.LedgerUnloadCmdConfig)
+	ledger.LedgerJumpCmdConfig.PersistentFlags().StringVar(&LedgerTradeFileName, trade.TradeFileFlagName, "trade-data.json", "name of file in data-local that holds the world trade facts, used for the travel time")
+	ledger.LedgerJumpCmdConfig.PersistentFlags().StringVar(&LedgerShipFileName, trade.ShipFileFlagName, trade.DefaultShipFilename, "name of file in data-local that holds the ship profile, used for the travel time")
+	ledger.LedgerCmdConfig.AddCommand(ledger.LedgerJumpCmdConfig)
+	ledger.LedgerCmdConfig.AddCommand(ledger.LedgerWaitCmdConfig)
+	ledger.LedgerCmdConfig.AddCommand(ledger.LedgerPayCmdConfig)
+	rootCmd.AddCommand(ledger.LedgerCmdConfig)
+
 	rootCmd.Execute()
 }